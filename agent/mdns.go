@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/ipv4"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// mdnsGroup is where mDNS queries are sent, queries from another port than 5353 are
+// answered straight back to the sender (RFC 6762 6.7) so no group has to be joined
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// serviceTypes is the DNS-SD meta query each host answers with the service types it advertises
+const serviceTypes = "_services._dns-sd._udp.local."
+
+// BrowseMDNS asks the network which mDNS service types, eg _googlecast._tcp, the hosts
+// advertise and returns them keyed by IP, answers are collected for wait
+func BrowseMDNS(wait time.Duration) (map[string][]string, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.ParseIP(*netInterface)})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if *netInterface != "" {
+		ifi, err := interfaceOf(net.ParseIP(*netInterface))
+		if err != nil {
+			return nil, err
+		}
+		err = ipv4.NewPacketConn(conn).SetMulticastInterface(ifi)
+		if err != nil {
+			return nil, err
+		}
+	}
+	name, err := dnsmessage.NewName(serviceTypes)
+	if err != nil {
+		return nil, err
+	}
+	query := dnsmessage.Message{Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET}}}
+	packet, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+	_, err = conn.WriteToUDP(packet, mdnsGroup)
+	if err != nil {
+		return nil, err
+	}
+	err = conn.SetReadDeadline(time.Now().Add(wait))
+	if err != nil {
+		return nil, err
+	}
+	services := make(map[string][]string)
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return services, nil
+		}
+		if err != nil {
+			return services, err
+		}
+		ip := from.IP.String()
+		for _, service := range parseServiceTypes(buf[:n]) {
+			if !slices.Contains(services[ip], service) {
+				services[ip] = append(services[ip], service)
+			}
+		}
+	}
+}
+
+// parseServiceTypes returns the service types in the answers of an mDNS response to the serviceTypes query
+func parseServiceTypes(packet []byte) []string {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(packet); err != nil {
+		return nil
+	}
+	found := make([]string, 0)
+	for _, answer := range append(msg.Answers, msg.Additionals...) {
+		ptr, ok := answer.Body.(*dnsmessage.PTRResource)
+		if !ok || !strings.EqualFold(answer.Header.Name.String(), serviceTypes) {
+			continue
+		}
+		found = append(found, strings.TrimSuffix(ptr.PTR.String(), ".local."))
+	}
+	return found
+}
+
+// interfaceOf returns the network interface with ip, the -interface is an address
+func interfaceOf(ip net.IP) (*net.Interface, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	for _, ifi := range interfaces {
+		addrs, err := ifi.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			if network, ok := addr.(*net.IPNet); ok && network.IP.Equal(ip) {
+				return &ifi, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no interface has the address %s", ip)
+}
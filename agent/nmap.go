@@ -2,10 +2,12 @@ package agent
 
 import (
 	"context"
+	"fmt"
 	"github.com/Ullaakut/nmap"
 	pb "github.com/beaujr/nmap_prometheus/proto"
 	"log"
 	"net"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +16,8 @@ import (
 // NetScanner interface for Scanning and returning AddressRequests
 type NetScanner interface {
 	Scan() ([]*pb.AddressRequest, error)
+	// ServiceScan is a port scan with version detection of the same hosts
+	ServiceScan() ([]*pb.AddressRequest, error)
 	GetInterface() string
 }
 
@@ -51,14 +55,28 @@ func NewScanner() NetScanner {
 	}
 	opts := []func(scanner *nmap.Scanner){
 		nmap.WithTargets(*subnet),
-		nmap.WithPingScan(),
 	}
 	if len(*dnsServers) > 0 {
 		opts = append(opts, nmap.WithCustomDNSServers(strings.Split(*dnsServers, ",")...))
 	} else {
 		opts = append(opts, nmap.WithSystemDNS())
 	}
-	return &NetworkScanner{home: *Home, subnet: *subnet, localAddrs: localAddresses, options: opts, nic: nic}
+	if *versionIntensity < 0 || *versionIntensity > 9 {
+		log.Fatalf("-service-version-intensity must be from 0 to 9, not %d", *versionIntensity)
+	}
+	serviceOpts := append(slices.Clip(opts),
+		nmap.WithPorts(strings.Split(*servicePorts, ",")...),
+		nmap.WithServiceInfo(),
+		nmap.WithVersionIntensity(int16(*versionIntensity)),
+	)
+	if *osDetection {
+		if *serviceScanInterval == 0 {
+			log.Println("OS detection only runs during service scans, set -service-scan-interval")
+		}
+		serviceOpts = append(serviceOpts, nmap.WithOSDetection())
+	}
+	opts = append(opts, nmap.WithPingScan())
+	return &NetworkScanner{home: *Home, subnet: *subnet, localAddrs: localAddresses, options: opts, serviceOptions: serviceOpts, nic: nic}
 }
 
 // NetworkScanner is an implementation of the NetScanner
@@ -69,16 +87,59 @@ type NetworkScanner struct {
 	nic        string
 	localAddrs map[string]string
 	options    []func(scanner *nmap.Scanner)
+	// serviceOptions are used by ServiceScan in place of options
+	serviceOptions []func(scanner *nmap.Scanner)
+}
+
+// bestOSMatch returns the most accurate OS match nmap found for host, nil when it found none
+func bestOSMatch(host nmap.Host) *pb.OSMatch {
+	var best *nmap.OSMatch
+	for i, match := range host.OS.Matches {
+		if best == nil || match.Accuracy > best.Accuracy {
+			best = &host.OS.Matches[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	os := &pb.OSMatch{Name: best.Name, Accuracy: int32(best.Accuracy)}
+	// the classes of a match are ordered by accuracy too
+	if len(best.Classes) > 0 {
+		os.Family = best.Classes[0].Family
+		os.Generation = best.Classes[0].OSGeneration
+		os.Vendor = best.Classes[0].Vendor
+	}
+	return os
+}
+
+// serviceBanner formats the version nmap detected like its own output eg OpenSSH 8.9p1 (Ubuntu Linux)
+func serviceBanner(service nmap.Service) string {
+	banner := strings.TrimSpace(service.Product + " " + service.Version)
+	if service.ExtraInfo != "" {
+		banner = strings.TrimSpace(fmt.Sprintf("%s (%s)", banner, service.ExtraInfo))
+	}
+	return banner
 }
 
 // Scan executes the nmap binary and parses the result
 func (ns *NetworkScanner) Scan() ([]*pb.AddressRequest, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	return ns.run(ns.options, 5*time.Minute, false)
+}
+
+// ServiceScan executes the nmap binary with the service scan options and parses the result
+func (ns *NetworkScanner) ServiceScan() ([]*pb.AddressRequest, error) {
+	log.Printf("Service scan of %s ports %s", ns.subnet, *servicePorts)
+	// version detection probes every open port so it is given longer
+	return ns.run(ns.serviceOptions, 30*time.Minute, true)
+}
+
+func (ns *NetworkScanner) run(options []func(scanner *nmap.Scanner), timeout time.Duration, serviceScan bool) ([]*pb.AddressRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	// Equivalent to `/usr/local/bin/nmap -p 80,443,843 google.com facebook.com youtube.com`,
 	// with a 5 minute timeout.
-	opts := append(ns.options, nmap.WithContext(ctx))
+	opts := append(slices.Clip(options), nmap.WithContext(ctx))
 	scanner, err := nmap.NewScanner(opts...)
 	//scanner, err := nmap.NewScanner(
 	//	nmap.WithTargets(ns.subnet),
@@ -121,6 +182,19 @@ func (ns *NetworkScanner) Scan() ([]*pb.AddressRequest, error) {
 		for _, hostnames := range host.Hostnames {
 			item.Hosts = append(item.Hosts, hostnames.Name)
 		}
+		item.PortsScanned = serviceScan
+		item.Os = bestOSMatch(host)
+		for _, port := range host.Ports {
+			if port.State.State != "open" {
+				continue
+			}
+			item.OpenPorts = append(item.OpenPorts, &pb.OpenPort{
+				Port:     int32(port.ID),
+				Protocol: port.Protocol,
+				Service:  port.Service.Name,
+				Banner:   serviceBanner(port.Service),
+			})
+		}
 		rstt, err := strconv.Atoi(host.Times.SRTT)
 		if err != nil {
 			rstt = 1000
@@ -4,12 +4,17 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"github.com/beaujr/nmap_prometheus/broker"
+	"github.com/beaujr/nmap_prometheus/certs"
 	pb "github.com/beaujr/nmap_prometheus/proto"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/go-ble/ble"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"log"
 	"math"
 	"net"
@@ -28,6 +33,12 @@ var (
 	agentId      = flag.String("agentId", "nmapAgent", "Identify Agent, if left blank will be the Machines ID")
 	apiKey       = flag.String("apikey", "apikey", "API KEY for access")
 	dnsServers   = flag.String("dns-servers", "", "comma separated Custom dns servers eg: 192.168.1.1,192,168.1.9")
+
+	serviceScanInterval = flag.Int("service-scan-interval", 0, "Seconds between port and service scans run alongside the ping scans, 0 disables them")
+	servicePorts        = flag.String("service-ports", "21,22,23,25,53,80,139,443,445,515,548,554,631,1883,3389,5000,5900,8008,8009,8080,8443,9100,62078", "comma separated ports and ranges the service scan probes")
+	versionIntensity    = flag.Int("service-version-intensity", 7, "Version detection intensity of the service scan from 0 (light) to 9 (all probes)")
+	osDetection         = flag.Bool("os-detection", false, "Fingerprint the operating system of devices during service scans, needs root")
+	mdnsWait            = flag.Int("mdns-wait", 2, "Seconds to collect the mDNS service types hosts advertise after each ping scan, 0 disables it")
 )
 
 const (
@@ -44,10 +55,24 @@ type Reporter struct {
 	conn       *grpc.ClientConn
 	ignoreList map[string]bool
 	Nmap       NetScanner
+	mqtt       mqtt.Client
+}
+
+// transport returns the TLS credentials from the -tls flags or insecure when they are blank
+func transport() (grpc.DialOption, error) {
+	reloader, err := certs.FromFlags()
+	if err != nil || reloader == nil {
+		return grpc.WithInsecure(), err
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(reloader.ClientConfig())), nil
 }
 
 func dial(address string) (*grpc.ClientConn, error) {
-	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	creds, err := transport()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.Dial(address, creds)
 	if *netInterface != "" {
 		localAddrDialier := &net.Dialer{
 			LocalAddr: &net.TCPAddr{
@@ -55,7 +80,7 @@ func dial(address string) (*grpc.ClientConn, error) {
 				Port: 0,
 			},
 		}
-		conn, err = grpc.Dial(address, grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		conn, err = grpc.Dial(address, creds, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
 			return localAddrDialier.DialContext(ctx, "tcp", addr)
 		}))
 
@@ -70,15 +95,22 @@ func NewReporter() Reporter {
 		log.Print(err)
 	}
 	ignoreList := make(map[string]bool)
+	var client mqtt.Client
+	if broker.Enabled() {
+		client, err = broker.Connect(fmt.Sprintf("nmap_prometheus-%s", *agentId), nil)
+		if err != nil {
+			log.Print(err)
+		}
+	}
 	if *bleEnabled {
 		bls, err := NewBeaconScanner()
 		if err != nil {
 			log.Print(err)
 		}
-		return Reporter{BleScanner: bls, Home: *Home, conn: conn, id: *agentId, ignoreList: ignoreList, Nmap: nil}
+		return Reporter{BleScanner: bls, Home: *Home, conn: conn, id: *agentId, ignoreList: ignoreList, Nmap: nil, mqtt: client}
 	}
 
-	return Reporter{BleScanner: nil, Home: *Home, conn: conn, id: *agentId, ignoreList: ignoreList, Nmap: NewScanner()}
+	return Reporter{BleScanner: nil, Home: *Home, conn: conn, id: *agentId, ignoreList: ignoreList, Nmap: NewScanner(), mqtt: client}
 
 }
 func (r *Reporter) buildClient() (pb.HomeDetectorClient, context.Context, context.CancelFunc) {
@@ -91,8 +123,37 @@ func (r *Reporter) buildClient() (pb.HomeDetectorClient, context.Context, contex
 	return client, ctx, cancelFunc
 }
 
-// Addresses reports pb.AddressesRequest to the GRPC server
+// publish sends msg to the agents topic of kind on the MQTT broker
+func (r *Reporter) publish(kind string, msg proto.Message) error {
+	payload, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	token := r.mqtt.Publish(broker.Topic(r.Home, r.id, kind), 1, false, payload)
+	if !token.WaitTimeout(time.Second * (time.Duration(*timeout))) {
+		return fmt.Errorf("timed out publishing to %s", broker.Topic(r.Home, r.id, kind))
+	}
+	return token.Error()
+}
+
+// mqttMetadata returns the details buildClient sends as headers for MQTT messages
+func (r *Reporter) mqttMetadata() []*pb.Metadata {
+	if r.Nmap == nil {
+		return []*pb.Metadata{{Key: "type", Value: BluetoothType}}
+	}
+	return []*pb.Metadata{{Key: "type", Value: NetworkType}, {Key: "interface", Value: r.Nmap.GetInterface()}}
+}
+
+// Addresses reports pb.AddressesRequest to the GRPC server or MQTT broker
 func (r *Reporter) Addresses(items []*pb.AddressRequest) error {
+	if r.mqtt != nil {
+		return r.publish(broker.AddressesTopic, &pb.MQTTAddressRequest{
+			Agent:     &pb.MQTTAgent{Home: r.Home, Id: r.id},
+			Addresses: items,
+			Timestamp: time.Now().Unix(),
+			Metadata:  r.mqttMetadata(),
+		})
+	}
 	gAddr := pb.AddressesRequest{Addresses: items}
 	c, ctx, cancel := r.buildClient()
 	defer cancel()
@@ -104,8 +165,11 @@ func (r *Reporter) Addresses(items []*pb.AddressRequest) error {
 	return nil
 }
 
-// Address reports pb.AddressRequest to the GRPC server
+// Address reports pb.AddressRequest to the GRPC server or MQTT broker
 func (r *Reporter) Address(items []*pb.AddressRequest) error {
+	if r.mqtt != nil {
+		return r.Addresses(items)
+	}
 	c, ctx, cancel := r.buildClient()
 	defer cancel()
 	for _, item := range items {
@@ -128,6 +192,19 @@ func (r *Reporter) AdvHandler(a ble.Advertisement) {
 		log.Println(fmt.Sprintf("Not reporting ble: %s", mac))
 		return
 	}
+	if r.mqtt != nil {
+		// without an ack from the server nothing is added to the ignore list
+		err := r.publish(broker.BleTopic, &pb.MQTTBleRequest{
+			Agent:     &pb.MQTTAgent{Home: r.Home, Id: r.id},
+			Bles:      &pb.BleRequest{Key: mac, Distance: float32(distance)},
+			Timestamp: time.Now().Unix(),
+			Metadata:  r.mqttMetadata(),
+		})
+		if err != nil {
+			log.Println(fmt.Sprintf("MQTT Error: %s", err.Error()))
+		}
+		return
+	}
 	c, ctx, cancel := r.buildClient()
 	defer cancel()
 	response, err := c.Ack(ctx, &pb.BleRequest{Key: mac, Distance: float32(distance)})
@@ -141,15 +218,56 @@ func (r *Reporter) AdvHandler(a ble.Advertisement) {
 	r.ignoreList[mac] = response.Acknowledged
 }
 
+// report sends addresses in bulk when there are more than -bulk of them
+func (r *Reporter) report(addresses []*pb.AddressRequest) error {
+	if len(addresses) > *bulk {
+		return r.bulkReport(addresses)
+	}
+	return r.Address(addresses)
+}
+
+// addServices sets the mDNS service types each of addresses advertises
+func addServices(addresses []*pb.AddressRequest) {
+	if *mdnsWait <= 0 || len(addresses) == 0 {
+		return
+	}
+	services, err := BrowseMDNS(time.Duration(*mdnsWait) * time.Second)
+	if err != nil {
+		log.Printf("unable to browse mDNS: %v", err)
+		return
+	}
+	for _, address := range addresses {
+		address.Services = services[address.GetIp()]
+	}
+}
+
+// ProcessServices runs a service scan every -service-scan-interval and reports it, it runs
+// next to ProcessNMAP so the ping scans keep reporting presence during the long service scans
+func (r *Reporter) ProcessServices() {
+	for {
+		addresses, err := r.Nmap.ServiceScan()
+		if err != nil {
+			log.Printf("unable to run nmap service scan: %v", err)
+		} else if err = r.report(addresses); err != nil {
+			log.Printf("unable to report service scan: %v", err)
+		}
+		time.Sleep(time.Duration(*serviceScanInterval) * time.Second)
+	}
+}
+
 // ProcessNMAP scans the network and reports to nmap server
 func (r *Reporter) ProcessNMAP() {
 	errors := 0
+	if *serviceScanInterval > 0 && !*script {
+		go r.ProcessServices()
+	}
 	for {
 		addresses, err := r.Nmap.Scan()
 		if err != nil {
 			log.Printf("unable to run nmap scan: %v", err)
 			errors++
 		}
+		addServices(addresses)
 		//addresses := make([]*pb.AddressRequest, 0)
 		//addresses = append(addresses, &pb.AddressRequest{Mac: "0000", Ip: "192.168.16.2"})
 		//err := fmt.Errorf("not a real error")
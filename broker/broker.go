@@ -0,0 +1,74 @@
+package broker
+
+import (
+	"flag"
+	"fmt"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"strings"
+	"time"
+)
+
+var (
+	address  = flag.String("mqtt", "", "MQTT broker eg: tcp://192.168.1.190:1883, leave blank to report over gRPC only")
+	user     = flag.String("mqttUser", "", "MQTT username")
+	password = flag.String("mqttPassword", "", "MQTT password")
+	prefix   = flag.String("mqttPrefix", "nmap_prometheus", "Topic prefix agents publish under")
+)
+
+const (
+	// AddressesTopic is the last segment of network scan topics
+	AddressesTopic = "addresses"
+	// BleTopic is the last segment of bluetooth topics
+	BleTopic = "ble"
+)
+
+// Enabled reports whether a broker was configured with -mqtt
+func Enabled() bool {
+	return *address != ""
+}
+
+// Connect returns a client connected to the -mqtt broker as clientID, onConnect
+// runs after every (re)connection so is where subscriptions belong
+func Connect(clientID string, onConnect mqtt.OnConnectHandler) (mqtt.Client, error) {
+	if !Enabled() {
+		return nil, fmt.Errorf("no -mqtt broker configured")
+	}
+	opts := mqtt.NewClientOptions().
+		AddBroker(*address).
+		SetClientID(clientID).
+		SetUsername(*user).
+		SetPassword(*password).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOnConnectHandler(onConnect)
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(30 * time.Second) {
+		return nil, fmt.Errorf("timed out connecting to %s", *address)
+	}
+	return client, token.Error()
+}
+
+// Topic returns <prefix>/<home>/<agent>/<kind>
+func Topic(home, agent, kind string) string {
+	return Path(home, agent, kind)
+}
+
+// Path joins segments under the -mqttPrefix
+func Path(segments ...string) string {
+	return strings.Join(append([]string{*prefix}, segments...), "/")
+}
+
+// ParseTopic returns the home, agent and kind of a Topic
+func ParseTopic(topic string) (home, agent, kind string, err error) {
+	segments := strings.Split(strings.TrimPrefix(topic, *prefix+"/"), "/")
+	if !strings.HasPrefix(topic, *prefix+"/") || len(segments) != 3 {
+		return "", "", "", fmt.Errorf("%s is not a %s/<home>/<agent>/<kind> topic", topic, *prefix)
+	}
+	return segments[0], segments[1], segments[2], nil
+}
+
+// Subscription returns the topic filter matching kind from every home and agent
+func Subscription(kind string) string {
+	return Topic("+", "+", kind)
+}
@@ -0,0 +1,185 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	certFile = flag.String("tls-cert", "", "Path to the PEM certificate, leave blank to connect without TLS")
+	keyFile  = flag.String("tls-key", "", "Path to the PEM private key of -tls-cert")
+	caFile   = flag.String("tls-ca", "", "Path to the PEM CA bundle, the server requires client certificates signed by it")
+)
+
+// Reloader serves a certificate and CA pool from disk, reloading them when the files change
+type Reloader struct {
+	sync.Mutex
+	certFile, keyFile, caFile string
+	cert                      *tls.Certificate
+	pool                      *x509.CertPool
+	certMod, caMod            time.Time
+}
+
+// NewReloader loads the certificate in certFile/keyFile and CA bundle in caFile, either may be blank
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, caFile: caFile}
+	if certFile != "" {
+		if _, err := r.certificate(); err != nil {
+			return nil, err
+		}
+	}
+	if caFile != "" {
+		if _, err := r.caPool(); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// modTime returns the newest modification time of files
+func modTime(files ...string) (time.Time, error) {
+	var newest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return newest, err
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest, nil
+}
+
+// certificate returns the current certificate, keeping the last good one if the files are mid rotation
+func (r *Reloader) certificate() (*tls.Certificate, error) {
+	r.Lock()
+	defer r.Unlock()
+	mod, err := modTime(r.certFile, r.keyFile)
+	if err == nil && (r.cert == nil || !mod.Equal(r.certMod)) {
+		var cert tls.Certificate
+		cert, err = tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err == nil {
+			r.cert = &cert
+			r.certMod = mod
+		}
+	}
+	if r.cert == nil {
+		return nil, err
+	}
+	return r.cert, nil
+}
+
+// caPool returns the current CA pool, keeping the last good one if the file is mid rotation
+func (r *Reloader) caPool() (*x509.CertPool, error) {
+	r.Lock()
+	defer r.Unlock()
+	mod, err := modTime(r.caFile)
+	if err == nil && (r.pool == nil || !mod.Equal(r.caMod)) {
+		var data []byte
+		data, err = os.ReadFile(r.caFile)
+		if err == nil {
+			pool := x509.NewCertPool()
+			if pool.AppendCertsFromPEM(data) {
+				r.pool = pool
+				r.caMod = mod
+			} else {
+				err = fmt.Errorf("no certificates in %s", r.caFile)
+			}
+		}
+	}
+	if r.pool == nil {
+		return nil, err
+	}
+	return r.pool, nil
+}
+
+// HasCertificate reports whether there is a certificate to present
+func (r *Reloader) HasCertificate() bool {
+	return r.certFile != ""
+}
+
+// ServerConfig returns the server tls.Config, client certificates are required when there is a CA
+func (r *Reloader) ServerConfig() *tls.Config {
+	base := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.certificate()
+		},
+	}
+	if r.caFile == "" {
+		return base
+	}
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		pool, err := r.caPool()
+		if err != nil {
+			return nil, err
+		}
+		config := base.Clone()
+		config.GetConfigForClient = nil
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		return config, nil
+	}
+	return base
+}
+
+// ClientConfig returns the agent tls.Config, the server is verified against the CA when there is one
+func (r *Reloader) ClientConfig() *tls.Config {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if r.certFile != "" {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.certificate()
+		}
+	}
+	if r.caFile != "" {
+		// RootCAs can't change after the config is handed to grpc so the chain is verified here
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			pool, err := r.caPool()
+			if err != nil {
+				return err
+			}
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("server presented no certificate")
+			}
+			intermediates := x509.NewCertPool()
+			for _, cert := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+			_, err = cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         pool,
+				Intermediates: intermediates,
+			})
+			return err
+		}
+	}
+	return config
+}
+
+// FromFlags returns the Reloader for -tls-cert/-tls-key/-tls-ca or nil when TLS is disabled
+func FromFlags() (*Reloader, error) {
+	if *certFile == "" && *caFile == "" {
+		return nil, nil
+	}
+	if (*certFile == "") != (*keyFile == "") {
+		return nil, fmt.Errorf("-tls-cert and -tls-key must be set together")
+	}
+	return NewReloader(*certFile, *keyFile, *caFile)
+}
+
+// Identity splits a client certificate common name of <home>/<agent id> or <agent id>
+func Identity(cert *x509.Certificate) (home, agent string) {
+	home, agent, found := strings.Cut(cert.Subject.CommonName, "/")
+	if !found {
+		return "", home
+	}
+	return home, agent
+}
@@ -0,0 +1,189 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+// issue writes a certificate for cn signed by the CA to dir and returns the cert and key paths
+func (ca *testCA) issue(t *testing.T, dir, cn string, serial int64) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := strings.ReplaceAll(cn, "/", "_")
+	certPath, keyPath := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	writePem(t, certPath, "CERTIFICATE", der, serial)
+	writePem(t, keyPath, "EC PRIVATE KEY", keyDer, serial)
+	return certPath, keyPath
+}
+
+// writePem writes the block with a distinct mod time per serial so rewrites are always noticed
+func writePem(t *testing.T, path, blockType string, der []byte, serial int64) {
+	t.Helper()
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod := time.Now().Add(time.Duration(serial) * time.Minute)
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutualTLSReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caPath := filepath.Join(dir, "ca.crt")
+	writePem(t, caPath, "CERTIFICATE", ca.cert.Raw, 0)
+	serverCert, serverKey := ca.issue(t, dir, "server", 2)
+	clientCert, clientKey := ca.issue(t, dir, "aus/lounge-pi", 3)
+
+	server, err := NewReloader(serverCert, serverKey, caPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewReloader(clientCert, clientKey, caPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", server.ServerConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	peers := make(chan *x509.Certificate, 2)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			if err := tlsConn.Handshake(); err == nil {
+				peers <- tlsConn.ConnectionState().PeerCertificates[0]
+			}
+			conn.Close()
+		}
+	}()
+
+	handshake := func() *x509.Certificate {
+		t.Helper()
+		config := client.ClientConfig()
+		config.ServerName = "localhost"
+		conn, err := tls.Dial("tcp", lis.Addr().String(), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		if home, agent := Identity(<-peers); home != "aus" || agent != "lounge-pi" {
+			t.Errorf("unexpected client identity %s/%s", home, agent)
+		}
+		return conn.ConnectionState().PeerCertificates[0]
+	}
+	if serial := handshake().SerialNumber.Int64(); serial != 2 {
+		t.Fatalf("expected server certificate 2, got %d", serial)
+	}
+	ca.issue(t, dir, "server", 4)
+	if serial := handshake().SerialNumber.Int64(); serial != 4 {
+		t.Errorf("expected the rotated server certificate 4, got %d", serial)
+	}
+}
+
+func TestServerRejectsUnknownClient(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t)
+	caPath := filepath.Join(dir, "ca.crt")
+	writePem(t, caPath, "CERTIFICATE", ca.cert.Raw, 0)
+	serverCert, serverKey := ca.issue(t, dir, "server", 2)
+	server, err := NewReloader(serverCert, serverKey, caPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", server.ServerConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	go func() {
+		conn, err := lis.Accept()
+		if err == nil {
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	anonymous, err := NewReloader("", "", caPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := anonymous.ClientConfig()
+	config.ServerName = "localhost"
+	conn, err := tls.Dial("tcp", lis.Addr().String(), config)
+	if err == nil {
+		// TLS 1.3 reports the missing client certificate on the first read
+		_, err = conn.Read(make([]byte, 1))
+		conn.Close()
+	}
+	if err == nil {
+		t.Error("expected a client without a certificate to be rejected")
+	}
+}
@@ -0,0 +1,380 @@
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory is an in-memory etcdv3.KV, etcdv3.Lease and etcdv3.Watcher, leases
+// expire against a manual clock which only moves forward through Advance
+type Memory struct {
+	sync.Mutex
+	etcdv3.KV
+	now       time.Time
+	rev       int64
+	nextLease int64
+	items     map[string]*mvccpb.KeyValue
+	leases    map[etcdv3.LeaseID]*memoryLease
+	watches   map[*memoryWatch]bool
+}
+
+type memoryLease struct {
+	ttl     int64
+	expires time.Time
+	keys    map[string]bool
+}
+
+// memoryWatch queues events for a watcher so writers never block on a slow reader
+type memoryWatch struct {
+	sync.Mutex
+	key, end []byte
+	pending  []*etcdv3.Event
+	notify   chan struct{}
+	canceled bool
+}
+
+// NewMemory returns an empty Memory with its clock set to now
+func NewMemory() *Memory {
+	m := &Memory{
+		now:     time.Now(),
+		items:   make(map[string]*mvccpb.KeyValue),
+		leases:  make(map[etcdv3.LeaseID]*memoryLease),
+		watches: make(map[*memoryWatch]bool),
+	}
+	m.KV = etcdv3.NewKVFromKVClient(memoryKV{m}, nil)
+	return m
+}
+
+// Now returns the current time of the manual clock
+func (m *Memory) Now() time.Time {
+	m.Lock()
+	defer m.Unlock()
+	return m.now
+}
+
+// Advance moves the clock forward by d and expires any leases which ran out
+func (m *Memory) Advance(d time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+	m.now = m.now.Add(d)
+	m.expire()
+}
+
+func (m *Memory) expire() {
+	for id, lease := range m.leases {
+		if !m.now.Before(lease.expires) {
+			m.revoke(id)
+		}
+	}
+}
+
+func (m *Memory) revoke(id etcdv3.LeaseID) {
+	lease, ok := m.leases[id]
+	if !ok {
+		return
+	}
+	m.rev++
+	for key := range lease.keys {
+		m.remove(key)
+	}
+	delete(m.leases, id)
+}
+
+// remove deletes key and notifies watchers, the caller bumps the revision
+func (m *Memory) remove(key string) {
+	if _, ok := m.items[key]; !ok {
+		return
+	}
+	delete(m.items, key)
+	m.emit(&etcdv3.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte(key), ModRevision: m.rev}})
+}
+
+func (m *Memory) emit(event *etcdv3.Event) {
+	for w := range m.watches {
+		if !inRange(string(event.Kv.Key), w.key, w.end) {
+			continue
+		}
+		w.Lock()
+		w.pending = append(w.pending, event)
+		w.Unlock()
+		select {
+		case w.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (m *Memory) header() *etcdserverpb.ResponseHeader {
+	return &etcdserverpb.ResponseHeader{Revision: m.rev}
+}
+
+// inRange reports whether k is within the etcd range [key, end)
+func inRange(k string, key, end []byte) bool {
+	switch {
+	case len(end) == 0:
+		return k == string(key)
+	case bytes.Equal(end, []byte{0}):
+		return k >= string(key)
+	}
+	return k >= string(key) && k < string(end)
+}
+
+// match returns the keys in the range [key, end) in order
+func (m *Memory) match(key, end []byte) []string {
+	keys := make([]string, 0)
+	for k := range m.items {
+		if inRange(k, key, end) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// memoryKV is the etcdserverpb.KVClient behind the etcdv3.KV of a Memory, the client
+// turns the ops and their options into the requests it serves
+type memoryKV struct {
+	m *Memory
+}
+
+func (kv memoryKV) Range(ctx context.Context, in *etcdserverpb.RangeRequest, opts ...grpc.CallOption) (*etcdserverpb.RangeResponse, error) {
+	m := kv.m
+	m.Lock()
+	defer m.Unlock()
+	m.expire()
+	keys := m.match(in.Key, in.RangeEnd)
+	resp := &etcdserverpb.RangeResponse{Header: m.header(), Count: int64(len(keys))}
+	if in.CountOnly {
+		return resp, nil
+	}
+	if in.Limit > 0 && int64(len(keys)) > in.Limit {
+		keys = keys[:in.Limit]
+		resp.More = true
+	}
+	for _, k := range keys {
+		item := *m.items[k]
+		if in.KeysOnly {
+			item.Value = nil
+		}
+		resp.Kvs = append(resp.Kvs, &item)
+	}
+	return resp, nil
+}
+
+func (kv memoryKV) Put(ctx context.Context, in *etcdserverpb.PutRequest, opts ...grpc.CallOption) (*etcdserverpb.PutResponse, error) {
+	m := kv.m
+	m.Lock()
+	defer m.Unlock()
+	m.expire()
+	leaseID := etcdv3.LeaseID(in.Lease)
+	if leaseID != etcdv3.NoLease {
+		lease, ok := m.leases[leaseID]
+		if !ok {
+			return nil, rpctypes.ErrGRPCLeaseNotFound
+		}
+		lease.keys[string(in.Key)] = true
+	}
+	m.rev++
+	item, ok := m.items[string(in.Key)]
+	if !ok {
+		item = &mvccpb.KeyValue{Key: in.Key, CreateRevision: m.rev}
+		m.items[string(in.Key)] = item
+	}
+	// a key belongs to the lease it was last put with
+	if previous, ok := m.leases[etcdv3.LeaseID(item.Lease)]; ok && item.Lease != in.Lease {
+		delete(previous.keys, string(in.Key))
+	}
+	item.Value = in.Value
+	item.ModRevision = m.rev
+	item.Version++
+	item.Lease = in.Lease
+	event := *item
+	m.emit(&etcdv3.Event{Type: mvccpb.PUT, Kv: &event})
+	return &etcdserverpb.PutResponse{Header: m.header()}, nil
+}
+
+func (kv memoryKV) DeleteRange(ctx context.Context, in *etcdserverpb.DeleteRangeRequest, opts ...grpc.CallOption) (*etcdserverpb.DeleteRangeResponse, error) {
+	m := kv.m
+	m.Lock()
+	defer m.Unlock()
+	m.expire()
+	keys := m.match(in.Key, in.RangeEnd)
+	if len(keys) > 0 {
+		m.rev++
+	}
+	for _, k := range keys {
+		if lease, ok := m.leases[etcdv3.LeaseID(m.items[k].Lease)]; ok {
+			delete(lease.keys, k)
+		}
+		m.remove(k)
+	}
+	return &etcdserverpb.DeleteRangeResponse{Header: m.header(), Deleted: int64(len(keys))}, nil
+}
+
+// Txn fails, transactions are not supported by Memory
+func (kv memoryKV) Txn(ctx context.Context, in *etcdserverpb.TxnRequest, opts ...grpc.CallOption) (*etcdserverpb.TxnResponse, error) {
+	return nil, fmt.Errorf("memory: transactions are not supported")
+}
+
+func (kv memoryKV) Compact(ctx context.Context, in *etcdserverpb.CompactionRequest, opts ...grpc.CallOption) (*etcdserverpb.CompactionResponse, error) {
+	kv.m.Lock()
+	defer kv.m.Unlock()
+	return &etcdserverpb.CompactionResponse{Header: kv.m.header()}, nil
+}
+
+func (m *Memory) Grant(ctx context.Context, ttl int64) (*etcdv3.LeaseGrantResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.nextLease++
+	id := etcdv3.LeaseID(m.nextLease)
+	m.leases[id] = &memoryLease{
+		ttl:     ttl,
+		expires: m.now.Add(time.Duration(ttl) * time.Second),
+		keys:    make(map[string]bool),
+	}
+	return &etcdv3.LeaseGrantResponse{ResponseHeader: m.header(), ID: id, TTL: ttl}, nil
+}
+
+func (m *Memory) Revoke(ctx context.Context, id etcdv3.LeaseID) (*etcdv3.LeaseRevokeResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.expire()
+	if _, ok := m.leases[id]; !ok {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+	m.revoke(id)
+	return &etcdv3.LeaseRevokeResponse{Header: m.header()}, nil
+}
+
+func (m *Memory) TimeToLive(ctx context.Context, id etcdv3.LeaseID, opts ...etcdv3.LeaseOption) (*etcdv3.LeaseTimeToLiveResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.expire()
+	lease, ok := m.leases[id]
+	if !ok {
+		return &etcdv3.LeaseTimeToLiveResponse{ResponseHeader: m.header(), ID: id, TTL: -1}, nil
+	}
+	keys := make([]string, 0, len(lease.keys))
+	for key := range lease.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	resp := &etcdv3.LeaseTimeToLiveResponse{
+		ResponseHeader: m.header(),
+		ID:             id,
+		TTL:            int64(lease.expires.Sub(m.now).Seconds()),
+		GrantedTTL:     lease.ttl,
+	}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, []byte(key))
+	}
+	return resp, nil
+}
+
+func (m *Memory) Leases(ctx context.Context) (*etcdv3.LeaseLeasesResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.expire()
+	resp := &etcdv3.LeaseLeasesResponse{ResponseHeader: m.header()}
+	for id := range m.leases {
+		resp.Leases = append(resp.Leases, etcdv3.LeaseStatus{ID: id})
+	}
+	sort.Slice(resp.Leases, func(i, j int) bool { return resp.Leases[i].ID < resp.Leases[j].ID })
+	return resp, nil
+}
+
+func (m *Memory) KeepAlive(ctx context.Context, id etcdv3.LeaseID) (<-chan *etcdv3.LeaseKeepAliveResponse, error) {
+	resp, err := m.KeepAliveOnce(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan *etcdv3.LeaseKeepAliveResponse, 1)
+	ch <- resp
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (m *Memory) KeepAliveOnce(ctx context.Context, id etcdv3.LeaseID) (*etcdv3.LeaseKeepAliveResponse, error) {
+	m.Lock()
+	defer m.Unlock()
+	m.expire()
+	lease, ok := m.leases[id]
+	if !ok {
+		return nil, rpctypes.ErrLeaseNotFound
+	}
+	lease.expires = m.now.Add(time.Duration(lease.ttl) * time.Second)
+	return &etcdv3.LeaseKeepAliveResponse{ResponseHeader: m.header(), ID: id, TTL: lease.ttl}, nil
+}
+
+// Watch streams the events for the range described by key and opts until ctx is done
+func (m *Memory) Watch(ctx context.Context, key string, opts ...etcdv3.OpOption) etcdv3.WatchChan {
+	op := etcdv3.OpGet(key, opts...)
+	w := &memoryWatch{key: op.KeyBytes(), end: op.RangeBytes(), notify: make(chan struct{}, 1)}
+	m.Lock()
+	m.watches[w] = true
+	m.Unlock()
+	out := make(chan etcdv3.WatchResponse)
+	go func() {
+		defer close(out)
+		defer func() {
+			m.Lock()
+			delete(m.watches, w)
+			m.Unlock()
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.notify:
+			}
+			w.Lock()
+			events, canceled := w.pending, w.canceled
+			w.pending = nil
+			w.Unlock()
+			select {
+			case <-ctx.Done():
+				return
+			case out <- etcdv3.WatchResponse{Events: events, Canceled: canceled}:
+			}
+			if canceled {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// CancelWatches cancels every watch like a compaction or a lost leader would
+func (m *Memory) CancelWatches() {
+	m.Lock()
+	defer m.Unlock()
+	for w := range m.watches {
+		w.Lock()
+		w.canceled = true
+		w.Unlock()
+		select {
+		case w.notify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (m *Memory) RequestProgress(ctx context.Context) error {
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,204 @@
+package house
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"os"
+	"slices"
+)
+
+var apiKeysFile = flag.String("apikeys", "", "Path to the api keys file, leave blank to accept unauthenticated requests")
+
+const (
+	// ScopeAgentReport allows agents to report devices
+	ScopeAgentReport = "agent-report"
+	// ScopeAdmin allows every rpc
+	ScopeAdmin = "admin"
+)
+
+// agentReportMethods are the rpcs an agent-report key may call
+var agentReportMethods = []string{
+	"/proto.HomeDetector/Ack",
+	"/proto.HomeDetector/Address",
+	"/proto.HomeDetector/Addresses",
+}
+
+// APIKey is a named key from the api keys file
+type APIKey struct {
+	Name   string   `yaml:"name"`
+	Key    string   `yaml:"key"`
+	Scopes []string `yaml:"scopes"`
+	// Clients are the agent ids allowed to use the key in their client header, blank for any
+	Clients []string `yaml:"clients"`
+}
+
+// KeyStore resolves the apikey header of a request
+type KeyStore interface {
+	// Lookup returns the key or nil if it is unknown
+	Lookup(key string) *APIKey
+}
+
+// fileKeyStore holds the keys read from the api keys file indexed by their hash
+type fileKeyStore struct {
+	keys map[string]*APIKey
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewKeyStore returns a KeyStore for keys
+func NewKeyStore(keys []*APIKey) (KeyStore, error) {
+	store := &fileKeyStore{keys: make(map[string]*APIKey)}
+	for _, key := range keys {
+		if key.Key == "" {
+			return nil, fmt.Errorf("api key %s has no key", key.Name)
+		}
+		if _, ok := store.keys[hashKey(key.Key)]; ok {
+			return nil, fmt.Errorf("api key %s is not unique", key.Name)
+		}
+		store.keys[hashKey(key.Key)] = key
+	}
+	return store, nil
+}
+
+// ReadKeyStore returns the KeyStore in the -apikeys file or nil when the flag is blank
+func ReadKeyStore() (KeyStore, error) {
+	if *apiKeysFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(*apiKeysFile)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*APIKey
+	err = yaml.Unmarshal(data, &keys)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyStore(keys)
+}
+
+func (f *fileKeyStore) Lookup(key string) *APIKey {
+	return f.keys[hashKey(key)]
+}
+
+// allows reports whether the key may call the full rpc method
+func (k *APIKey) allows(method string) bool {
+	if slices.Contains(k.Scopes, ScopeAdmin) {
+		return true
+	}
+	return slices.Contains(k.Scopes, ScopeAgentReport) && slices.Contains(agentReportMethods, method)
+}
+
+// authorize checks the apikey and client headers in ctx against keys for method
+func authorize(ctx context.Context, keys KeyStore, method string) error {
+	headers, _ := metadata.FromIncomingContext(ctx)
+	val := headers.Get("apikey")
+	if len(val) == 0 {
+		return status.Error(codes.Unauthenticated, "missing apikey")
+	}
+	key := keys.Lookup(val[0])
+	if key == nil {
+		return status.Error(codes.Unauthenticated, "invalid apikey")
+	}
+	if len(key.Clients) > 0 {
+		client := headers.Get("client")
+		if len(client) == 0 || !slices.Contains(key.Clients, client[0]) {
+			return status.Errorf(codes.Unauthenticated, "apikey %s is not bound to this client", key.Name)
+		}
+	}
+	if !key.allows(method) {
+		return status.Errorf(codes.PermissionDenied, "apikey %s may not call %s", key.Name, method)
+	}
+	return nil
+}
+
+// UnaryAuthInterceptor rejects unary calls without a valid apikey for the rpc
+func UnaryAuthInterceptor(keys KeyStore) gogrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, keys, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor rejects streams without a valid apikey for the rpc
+func StreamAuthInterceptor(keys KeyStore) gogrpc.StreamServerInterceptor {
+	return func(srv interface{}, ss gogrpc.ServerStream, info *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
+		if err := authorize(ss.Context(), keys, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// adminKey writes an error and returns false unless the apikey header of req is an admin key
+func adminKey(keys KeyStore, w http.ResponseWriter, req *http.Request) bool {
+	val := req.Header.Get("apikey")
+	if val == "" {
+		apiError(w, http.StatusUnauthorized, "missing apikey")
+		return false
+	}
+	key := keys.Lookup(val)
+	if key == nil {
+		apiError(w, http.StatusUnauthorized, "invalid apikey")
+		return false
+	}
+	if !slices.Contains(key.Scopes, ScopeAdmin) {
+		apiError(w, http.StatusForbidden, fmt.Sprintf("apikey %s may not change %s", key.Name, req.URL.Path))
+		return false
+	}
+	return true
+}
+
+// RequireAPIKey rejects HTTP requests that change state without an admin key in the apikey
+// header, reads stay open like the other HTTP endpoints. Every request passes when keys is nil
+func RequireAPIKey(keys KeyStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if keys == nil || req.Method == http.MethodGet || req.Method == http.MethodHead {
+			next.ServeHTTP(w, req)
+			return
+		}
+		if adminKey(keys, w, req) {
+			next.ServeHTTP(w, req)
+		}
+	})
+}
+
+// RequireStreamKey rejects every HTTP request without an admin key in the apikey header or,
+// as browser EventSources cannot set headers, the apikey query parameter. Every request passes when keys is nil
+func RequireStreamKey(keys KeyStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if keys == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+		if req.Header.Get("apikey") == "" {
+			req.Header.Set("apikey", req.URL.Query().Get("apikey"))
+		}
+		if adminKey(keys, w, req) {
+			next.ServeHTTP(w, req)
+		}
+	})
+}
+
+// RequireAdminKey rejects every HTTP request without an admin key in the apikey header
+func RequireAdminKey(keys KeyStore, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if adminKey(keys, w, req) {
+			next.ServeHTTP(w, req)
+		}
+	})
+}
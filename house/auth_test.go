@@ -0,0 +1,68 @@
+package house
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	keys, err := NewKeyStore([]*APIKey{
+		{Name: "lounge", Key: "agent-key", Scopes: []string{ScopeAgentReport}, Clients: []string{"lounge-pi"}},
+		{Name: "ops", Key: "admin-key", Scopes: []string{ScopeAdmin}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		headers []string
+		method  string
+		code    codes.Code
+	}{
+		{"missing key", []string{"client", "lounge-pi"}, "/proto.HomeDetector/Address", codes.Unauthenticated},
+		{"unknown key", []string{"apikey", "nope", "client", "lounge-pi"}, "/proto.HomeDetector/Address", codes.Unauthenticated},
+		{"agent reports", []string{"apikey", "agent-key", "client", "lounge-pi"}, "/proto.HomeDetector/Addresses", codes.OK},
+		{"agent from another client", []string{"apikey", "agent-key", "client", "garage-pi"}, "/proto.HomeDetector/Address", codes.Unauthenticated},
+		{"agent deletes", []string{"apikey", "agent-key", "client", "lounge-pi"}, "/proto.HomeDetector/DeleteDevice", codes.PermissionDenied},
+		{"admin deletes", []string{"apikey", "admin-key"}, "/proto.HomeDetector/DeleteDevice", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tt.headers...))
+			if code := status.Code(authorize(ctx, keys, tt.method)); code != tt.code {
+				t.Errorf("expected %s, got %s", tt.code, code)
+			}
+		})
+	}
+}
+
+func TestNewKeyStoreRejectsDuplicates(t *testing.T) {
+	_, err := NewKeyStore([]*APIKey{{Name: "a", Key: "same"}, {Name: "b", Key: "same"}})
+	if err == nil {
+		t.Error("expected duplicate keys to be rejected")
+	}
+}
+
+func TestRequireStreamKey(t *testing.T) {
+	keys, err := NewKeyStore([]*APIKey{{Name: "ops", Key: "admin-key", Scopes: []string{ScopeAdmin}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := RequireStreamKey(keys, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	for target, code := range map[string]int{
+		"/events":                  http.StatusUnauthorized,
+		"/events?apikey=nope":      http.StatusUnauthorized,
+		"/events?apikey=admin-key": http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != code {
+			t.Errorf("%s: expected %d, got %d", target, code, w.Code)
+		}
+	}
+}
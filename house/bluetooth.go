@@ -3,13 +3,12 @@ package house
 import (
 	"fmt"
 	pb "github.com/beaujr/nmap_prometheus/proto"
-	etcdv3 "go.etcd.io/etcd/client/v3"
 	"gopkg.in/yaml.v2"
 
 	"io/ioutil"
 	"log"
 	"os"
-	"strings"
+	"sort"
 )
 
 func writeBleDevices(devices []*pb.BleDevices) error {
@@ -44,52 +43,22 @@ func readBleConfig(filename string) ([]*pb.BleDevices, error) {
 }
 
 func (s *Server) ReadBleConfig() (map[string]*pb.BleDevices, error) {
-	var result map[string]*pb.BleDevices
-	result = make(map[string]*pb.BleDevices)
-	items, err := s.Kv.Get(s.GetContext(), BlesPrefix, etcdv3.WithPrefix())
-	if err != nil {
-		return nil, err
-	}
-	if items == nil {
-		return result, nil
-	}
-	i := 0
-	for i < int(items.Count) {
-		val := items.Kvs[i].Value
-		key := items.Kvs[i].Key
-		var dev *pb.BleDevices
-		err = yaml.Unmarshal(val, &dev)
-		if err != nil {
-			return nil, err
-		}
-		strKey := string(key)
-		newKey := strings.ReplaceAll(strKey, BlesPrefix, "")
-		result[string(newKey)] = dev
-		i++
-	}
-	return result, nil
+	return s.Store.ListBleDevices(s.GetContext())
 }
 
 func (s *Server) readBleConfigAsSlice() ([]*pb.BleDevices, error) {
-	var result []*pb.BleDevices
-	result = make([]*pb.BleDevices, 0)
-	items, err := s.Kv.Get(s.GetContext(), BlesPrefix, etcdv3.WithPrefix())
+	items, err := s.Store.ListBleDevices(s.GetContext())
 	if err != nil {
 		return nil, err
 	}
-	if items == nil {
-		return result, nil
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
 	}
-	i := 0
-	for i < int(items.Count) {
-		val := items.Kvs[i].Value
-		var dev *pb.BleDevices
-		err = yaml.Unmarshal(val, &dev)
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, dev)
-		i++
+	sort.Strings(keys)
+	result := make([]*pb.BleDevices, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, items[key])
 	}
 	return result, nil
 }
@@ -110,62 +79,19 @@ func uniqueBle(devices []*pb.BleDevices) ([]*pb.BleDevices, error) {
 	return list, nil
 }
 func (s *Server) writeBleDevice(item *pb.BleDevices) error {
-	d1, err := yaml.Marshal(item)
-	if err != nil {
-		log.Fatalf(err.Error())
-	}
-
-	key := fmt.Sprintf("%s%s", BlesPrefix, item.Id)
-	_, err = s.Kv.Put(s.GetContext(), key, string(d1))
-	return err
+	return s.Store.PutBleDevice(s.GetContext(), item)
 }
 
 func (s *Server) writeTc(item *pb.TimedCommands) error {
-	d1, err := yaml.Marshal(item)
-	if err != nil {
-		log.Fatalf(err.Error())
-	}
-
-	key := fmt.Sprintf("%s%s", tcPrefix, item.Id)
-	_, err = s.Kv.Put(s.GetContext(), key, string(d1))
-	return err
+	return s.Store.PutTimedCommand(s.GetContext(), item)
 }
 
 func (s *Server) deleteTc(item *pb.TimedCommands) error {
-	key := fmt.Sprintf("%s%s", tcPrefix, item.Id)
-	return s.deleteTcByKey(key)
-}
-
-func (s *Server) deleteTcByKey(key string) error {
-	_, err := s.Kv.Delete(s.GetContext(), key)
-	return err
+	return s.Store.DeleteTimedCommand(s.GetContext(), item.Id)
 }
 
 func (s *Server) getTcByOwner(owner string) (map[string]*pb.TimedCommands, error) {
-	var result map[string]*pb.TimedCommands
-	result = make(map[string]*pb.TimedCommands)
-	items, err := s.Kv.Get(s.GetContext(), fmt.Sprintf("%s%s", tcPrefix, owner), etcdv3.WithPrefix())
-	if err != nil {
-		return nil, err
-	}
-	if items == nil {
-		return result, nil
-	}
-	i := 0
-	for i < int(items.Count) {
-		val := items.Kvs[i].Value
-		key := items.Kvs[i].Key
-		var dev *pb.TimedCommands
-		err = yaml.Unmarshal(val, &dev)
-		if err != nil {
-			return nil, err
-		}
-		strKey := string(key)
-		newKey := strings.ReplaceAll(strKey, tcPrefix, "")
-		result[string(newKey)] = dev
-		i++
-	}
-	return result, nil
+	return s.Store.ListTimedCommands(s.GetContext(), owner)
 }
 
 func (s *Server) getTc() (map[string]*pb.TimedCommands, error) {
@@ -173,36 +99,20 @@ func (s *Server) getTc() (map[string]*pb.TimedCommands, error) {
 }
 
 func (s *Server) getTcById(id string) (*pb.TimedCommands, error) {
-	items, err := s.Kv.Get(s.GetContext(), fmt.Sprintf("%s%s", tcPrefix, id), etcdv3.WithPrefix())
+	items, err := s.Store.ListTimedCommands(s.GetContext(), id)
 	if err != nil {
 		return nil, err
 	}
-	if items.Count == 0 {
+	if len(items) == 0 {
 		return nil, fmt.Errorf("CQ with id:%s not found", id)
 	}
-	var dev *pb.TimedCommands
-	err = yaml.Unmarshal(items.Kvs[0].Value, &dev)
-	if err != nil {
-		return nil, err
-	}
-	return dev, nil
-}
-
-func (s *Server) getTcKeys() ([]*string, error) {
-	var result []*string
-	result = make([]*string, 0)
-	items, err := s.Kv.Get(s.GetContext(), tcPrefix, etcdv3.WithPrefix(), etcdv3.WithKeysOnly())
-	if err != nil {
-		return nil, err
-	}
-	if items == nil {
-		return result, nil
+	if item, ok := items[id]; ok {
+		return item, nil
 	}
-	i := 0
-	for i < int(items.Count) {
-		strKey := string(items.Kvs[i].Key)
-		result = append(result, &strKey)
-		i++
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
 	}
-	return result, nil
+	sort.Strings(keys)
+	return items[keys[0]], nil
 }
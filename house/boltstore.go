@@ -0,0 +1,557 @@
+package house
+
+import (
+	"context"
+	"encoding/json"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	devicesBucket       = []byte("devices")
+	blesBucket          = []byte("bles")
+	tcBucket            = []byte("cq")
+	peopleBucket        = []byte("people")
+	homesBucket         = []byte("homes")
+	aliveBucket         = []byte("alive")
+	notificationsBucket = []byte("notifications")
+	vendorsBucket       = []byte("vendors")
+	indexBucket         = []byte("index")
+)
+
+// BoltStore is an embedded implementation of the Store for running without an etcd cluster
+type BoltStore struct {
+	sync.Mutex
+	db       *bolt.DB
+	watchers map[*boltWatch]bool
+}
+
+// boltWatch is a WatchAlive subscriber
+type boltWatch struct {
+	ctx    context.Context
+	events chan AliveEvent
+}
+
+// boltLease is an alive value with its expiry, Expires is 0 for keys without a lease
+type boltLease struct {
+	Value   string `json:"value"`
+	TTL     int64  `json:"ttl"`
+	Expires int64  `json:"expires"`
+}
+
+func (l *boltLease) expired(now int64) bool {
+	return l.Expires != 0 && l.Expires <= now
+}
+
+// NewBoltStore opens (or creates) the bolt database at filename
+func NewBoltStore(filename string) (Store, error) {
+	db, err := bolt.Open(filename, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{devicesBucket, blesBucket, tcBucket, peopleBucket, homesBucket, aliveBucket, notificationsBucket, vendorsBucket, indexBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db, watchers: make(map[*boltWatch]bool)}, nil
+}
+
+func (b *BoltStore) get(bucket []byte, key string) []byte {
+	var val []byte
+	b.db.View(func(tx *bolt.Tx) error {
+		if v := tx.Bucket(bucket).Get([]byte(key)); v != nil {
+			val = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return val
+}
+
+func (b *BoltStore) put(bucket []byte, key string, item proto.Message) error {
+	d1, err := marshalRecord(item)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), d1)
+	})
+}
+
+// list returns the raw values in bucket whose key starts with prefix
+func (b *BoltStore) list(bucket []byte, prefix string) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, v = c.Next() {
+			result[string(k)] = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (b *BoltStore) deletePrefix(bucket []byte, prefix string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		// deleting while iterating skips keys so collect them first
+		keys := make([][]byte, 0)
+		c := tx.Bucket(bucket).Cursor()
+		for k, _ := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, _ = c.Next() {
+			keys = append(keys, append([]byte{}, k...))
+		}
+		for _, k := range keys {
+			if err := tx.Bucket(bucket).Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BoltStore) GetDevice(ctx context.Context, id string) (*pb.Devices, error) {
+	val := b.get(devicesBucket, id)
+	if val == nil {
+		return nil, nil
+	}
+	dev := &pb.Devices{}
+	err := unmarshalRecord(val, dev)
+	if err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+func (b *BoltStore) ListDevices(ctx context.Context) (map[string]*pb.Devices, error) {
+	items, err := b.list(devicesBucket, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*pb.Devices)
+	for key, val := range items {
+		dev := &pb.Devices{}
+		err = unmarshalRecord(val, dev)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = dev
+	}
+	return result, nil
+}
+
+func (b *BoltStore) ScanDevices(ctx context.Context, after string, limit int64) ([]string, []*pb.Devices, bool, error) {
+	keys := make([]string, 0)
+	result := make([]*pb.Devices, 0)
+	more := false
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(devicesBucket).Cursor()
+		k, v := c.Seek([]byte(after))
+		if k != nil && string(k) == after {
+			k, v = c.Next()
+		}
+		for ; k != nil; k, v = c.Next() {
+			if limit > 0 && int64(len(result)) == limit {
+				more = true
+				return nil
+			}
+			dev := &pb.Devices{}
+			if err := unmarshalRecord(v, dev); err != nil {
+				return err
+			}
+			keys = append(keys, string(k))
+			result = append(result, dev)
+		}
+		return nil
+	})
+	return keys, result, more, err
+}
+
+func (b *BoltStore) PutDevice(ctx context.Context, item *pb.Devices) error {
+	d1, err := marshalRecord(item)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(devicesBucket).Put([]byte(item.Id.UUID), d1)
+		if err != nil {
+			return err
+		}
+		for _, key := range indexKeys(item) {
+			err = tx.Bucket(indexBucket).Put([]byte(key), []byte(item.Id.UUID))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *BoltStore) FindDevices(ctx context.Context, home, ip string, hostnames []string) ([]*pb.Devices, error) {
+	result := make([]*pb.Devices, 0)
+	err := b.db.View(func(tx *bolt.Tx) error {
+		seen := make(map[string]bool)
+		for _, key := range findKeys(home, ip, hostnames) {
+			id := tx.Bucket(indexBucket).Get([]byte(key))
+			if id == nil || seen[string(id)] {
+				continue
+			}
+			seen[string(id)] = true
+			val := tx.Bucket(devicesBucket).Get(id)
+			if val == nil {
+				continue
+			}
+			dev := &pb.Devices{}
+			if err := unmarshalRecord(val, dev); err != nil {
+				return err
+			}
+			result = append(result, dev)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (b *BoltStore) DeleteDevice(ctx context.Context, id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(devicesBucket).Delete([]byte(id))
+	})
+}
+
+func (b *BoltStore) GetBleDevice(ctx context.Context, id string) (*pb.BleDevices, error) {
+	val := b.get(blesBucket, id)
+	if val == nil {
+		return nil, nil
+	}
+	dev := &pb.BleDevices{}
+	err := unmarshalRecord(val, dev)
+	if err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+func (b *BoltStore) ListBleDevices(ctx context.Context) (map[string]*pb.BleDevices, error) {
+	items, err := b.list(blesBucket, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*pb.BleDevices)
+	for key, val := range items {
+		dev := &pb.BleDevices{}
+		err = unmarshalRecord(val, dev)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = dev
+	}
+	return result, nil
+}
+
+func (b *BoltStore) PutBleDevice(ctx context.Context, item *pb.BleDevices) error {
+	return b.put(blesBucket, item.Id, item)
+}
+
+func (b *BoltStore) DeleteBleDevice(ctx context.Context, id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(blesBucket).Delete([]byte(id))
+	})
+}
+
+func (b *BoltStore) ListTimedCommands(ctx context.Context, prefix string) (map[string]*pb.TimedCommands, error) {
+	items, err := b.list(tcBucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*pb.TimedCommands)
+	for key, val := range items {
+		tc := &pb.TimedCommands{}
+		err = unmarshalRecord(val, tc)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = tc
+	}
+	return result, nil
+}
+
+func (b *BoltStore) PutTimedCommand(ctx context.Context, item *pb.TimedCommands) error {
+	return b.put(tcBucket, item.Id, item)
+}
+
+func (b *BoltStore) DeleteTimedCommand(ctx context.Context, id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(tcBucket).Delete([]byte(id))
+	})
+}
+
+func (b *BoltStore) DeleteTimedCommands(ctx context.Context, prefix string) error {
+	return b.deletePrefix(tcBucket, prefix)
+}
+
+func (b *BoltStore) GetPerson(ctx context.Context, name string) (*pb.People, error) {
+	val := b.get(peopleBucket, name)
+	if val == nil {
+		return nil, nil
+	}
+	human := &pb.People{}
+	err := unmarshalRecord(val, human)
+	if err != nil {
+		return nil, err
+	}
+	return human, nil
+}
+
+func (b *BoltStore) ListPeople(ctx context.Context) (map[string]*pb.People, error) {
+	items, err := b.list(peopleBucket, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*pb.People)
+	for key, val := range items {
+		human := &pb.People{}
+		err = unmarshalRecord(val, human)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = human
+	}
+	return result, nil
+}
+
+func (b *BoltStore) PutPerson(ctx context.Context, person *pb.People) error {
+	return b.put(peopleBucket, person.Name, person)
+}
+
+func (b *BoltStore) DeletePerson(ctx context.Context, name string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(peopleBucket).Delete([]byte(name))
+	})
+}
+
+func (b *BoltStore) ListHomes(ctx context.Context) (map[string]bool, error) {
+	items, err := b.list(homesBucket, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]bool)
+	for key, val := range items {
+		boolVal, _ := strconv.ParseBool(string(val))
+		result[key] = boolVal
+	}
+	return result, nil
+}
+
+func (b *BoltStore) PutHome(ctx context.Context, home string, empty bool) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(homesBucket).Put([]byte(home), []byte(strconv.FormatBool(empty)))
+	})
+}
+
+func (b *BoltStore) DeleteHome(ctx context.Context, home string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(homesBucket).Delete([]byte(home))
+	})
+}
+
+func (b *BoltStore) putLease(key string, lease *boltLease) error {
+	d1, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(aliveBucket).Put([]byte(key), d1)
+	})
+}
+
+func (b *BoltStore) GrantLease(ctx context.Context, home, mac, value string, ttl int64) error {
+	key := path.Join(home, mac)
+	now := time.Now().Unix()
+	lease := &boltLease{Value: value, TTL: ttl}
+	refreshed := false
+	if val := b.get(aliveBucket, key); val != nil {
+		existing := &boltLease{}
+		if err := json.Unmarshal(val, existing); err == nil && !existing.expired(now) {
+			// refreshing keeps the value the lease was granted with, a changed ttl applies from now
+			lease.Value = existing.Value
+			refreshed = true
+		}
+	}
+	if lease.TTL != 0 {
+		lease.Expires = now + lease.TTL
+	}
+	err := b.putLease(key, lease)
+	if err == nil && !refreshed {
+		b.emit(aliveEvent(key, lease.Value, false))
+	}
+	return err
+}
+
+func (b *BoltStore) PutAlive(ctx context.Context, home, mac, value string) error {
+	key := path.Join(home, mac)
+	err := b.putLease(key, &boltLease{Value: value})
+	if err == nil {
+		b.emit(aliveEvent(key, value, false))
+	}
+	return err
+}
+
+func (b *BoltStore) DeleteAlive(ctx context.Context, home, mac string) error {
+	key := path.Join(home, mac)
+	var deleted *boltLease
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		if val := tx.Bucket(aliveBucket).Get([]byte(key)); val != nil {
+			lease := &boltLease{}
+			if err := json.Unmarshal(val, lease); err == nil && !lease.expired(time.Now().Unix()) {
+				deleted = lease
+			}
+		}
+		return tx.Bucket(aliveBucket).Delete([]byte(key))
+	})
+	if err == nil && deleted != nil {
+		b.emit(aliveEvent(key, deleted.Value, true))
+	}
+	return err
+}
+
+func (b *BoltStore) ListAlive(ctx context.Context, home string) (map[string]string, error) {
+	items, err := b.list(aliveBucket, alivePrefix(home))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	result := make(map[string]string)
+	for key, val := range items {
+		lease := &boltLease{}
+		if err := json.Unmarshal(val, lease); err != nil {
+			return nil, err
+		}
+		if !lease.expired(now) {
+			result[key] = lease.Value
+		}
+	}
+	return result, nil
+}
+
+func (b *BoltStore) emit(event AliveEvent) {
+	b.Lock()
+	watchers := make([]*boltWatch, 0, len(b.watchers))
+	for w := range b.watchers {
+		watchers = append(watchers, w)
+	}
+	b.Unlock()
+	for _, w := range watchers {
+		select {
+		case w.events <- event:
+		case <-w.ctx.Done():
+		}
+	}
+}
+
+// sweep deletes the expired alive keys, bolt has no ttl so leases are expired here
+func (b *BoltStore) sweep() error {
+	now := time.Now().Unix()
+	expired := make([]AliveEvent, 0)
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		c := tx.Bucket(aliveBucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			lease := &boltLease{}
+			if err := json.Unmarshal(v, lease); err != nil || !lease.expired(now) {
+				continue
+			}
+			expired = append(expired, aliveEvent(string(k), lease.Value, true))
+		}
+		for _, event := range expired {
+			if err := tx.Bucket(aliveBucket).Delete([]byte(path.Join(event.Home, event.Mac))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, event := range expired {
+		b.emit(event)
+	}
+	return nil
+}
+
+func (b *BoltStore) WatchAlive(ctx context.Context) (<-chan AliveEvent, error) {
+	w := &boltWatch{ctx: ctx, events: make(chan AliveEvent, 64)}
+	b.Lock()
+	b.watchers[w] = true
+	b.Unlock()
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				b.Lock()
+				delete(b.watchers, w)
+				b.Unlock()
+				close(w.events)
+				return
+			case <-ticker.C:
+				if err := b.sweep(); err != nil {
+					log.Printf("sweeping alive keys failed: %v", err)
+				}
+			}
+		}
+	}()
+	return w.events, nil
+}
+
+// GetVendor reads the vendor as a boltLease, expired ones are left to be overwritten
+func (b *BoltStore) GetVendor(ctx context.Context, oui string) (string, bool, error) {
+	val := b.get(vendorsBucket, oui)
+	if val == nil {
+		return "", false, nil
+	}
+	lease := &boltLease{}
+	if err := json.Unmarshal(val, lease); err != nil {
+		return "", false, err
+	}
+	if lease.expired(time.Now().Unix()) {
+		return "", false, nil
+	}
+	return lease.Value, true, nil
+}
+
+func (b *BoltStore) PutVendor(ctx context.Context, oui, vendor string, ttl int64) error {
+	d1, err := json.Marshal(&boltLease{Value: vendor, TTL: ttl, Expires: time.Now().Unix() + ttl})
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(vendorsBucket).Put([]byte(oui), d1)
+	})
+}
+
+func (b *BoltStore) GetLastNotification(ctx context.Context) (*string, error) {
+	val := b.get(notificationsBucket, "last")
+	if val == nil {
+		return nil, nil
+	}
+	lastMessage := string(val)
+	return &lastMessage, nil
+}
+
+func (b *BoltStore) PutLastNotification(ctx context.Context, notification string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(notificationsBucket).Put([]byte("last"), []byte(notification))
+	})
+}
@@ -0,0 +1,358 @@
+package house
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+	"sort"
+	"strings"
+	"time"
+)
+
+// bundleVersion is the version of the Bundle written by ExportBundle
+const bundleVersion = 1
+
+// ExportBundle snapshots the devices, ble devices, people, homes and timed commands in store
+func ExportBundle(ctx context.Context, store Store) (*pb.Bundle, error) {
+	bundle := &pb.Bundle{Version: bundleVersion}
+	devices, err := store.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(devices) {
+		bundle.Devices = append(bundle.Devices, devices[key])
+	}
+	bles, err := store.ListBleDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(bles) {
+		bundle.Bles = append(bundle.Bles, bles[key])
+	}
+	people, err := store.ListPeople(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(people) {
+		bundle.People = append(bundle.People, people[key])
+	}
+	homes, err := store.ListHomes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(homes) {
+		bundle.Homes = append(bundle.Homes, &pb.Home{Name: key, Empty: homes[key]})
+	}
+	tcs, err := store.ListTimedCommands(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(tcs) {
+		bundle.Commands = append(bundle.Commands, tcs[key])
+	}
+	return bundle, nil
+}
+
+func sortedKeys[V any](items map[string]V) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// bundleKind is one kind of record being imported, keyed by id
+type bundleKind struct {
+	name    string
+	current map[string]proto.Message
+	wanted  map[string]proto.Message
+	put     func(item proto.Message) error
+	remove  func(id string) error
+}
+
+// bundleKinds reads the records of store alongside the ones in bundle
+func bundleKinds(ctx context.Context, store Store, bundle *pb.Bundle) ([]*bundleKind, error) {
+	devices := &bundleKind{
+		name:    "device",
+		current: make(map[string]proto.Message),
+		wanted:  make(map[string]proto.Message),
+		put:     func(item proto.Message) error { return importDevice(ctx, store, item.(*pb.Devices)) },
+	}
+	devices.remove = func(id string) error {
+		err := store.DeleteDevice(ctx, id)
+		if err != nil {
+			return err
+		}
+		return store.DeleteAlive(ctx, devices.current[id].(*pb.Devices).GetHome(), id)
+	}
+	items, err := store.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, item := range items {
+		devices.current[key] = item
+	}
+	for _, item := range bundle.GetDevices() {
+		devices.wanted[item.GetId().GetUUID()] = item
+	}
+
+	bles := &bundleKind{
+		name:    "ble",
+		current: make(map[string]proto.Message),
+		wanted:  make(map[string]proto.Message),
+		put:     func(item proto.Message) error { return store.PutBleDevice(ctx, item.(*pb.BleDevices)) },
+		remove:  func(id string) error { return store.DeleteBleDevice(ctx, id) },
+	}
+	bleItems, err := store.ListBleDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, item := range bleItems {
+		bles.current[key] = item
+	}
+	for _, item := range bundle.GetBles() {
+		bles.wanted[item.GetId()] = item
+	}
+
+	people := &bundleKind{
+		name:    "person",
+		current: make(map[string]proto.Message),
+		wanted:  make(map[string]proto.Message),
+		put:     func(item proto.Message) error { return store.PutPerson(ctx, item.(*pb.People)) },
+		remove:  func(id string) error { return store.DeletePerson(ctx, id) },
+	}
+	humans, err := store.ListPeople(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, item := range humans {
+		people.current[key] = item
+	}
+	for _, item := range bundle.GetPeople() {
+		people.wanted[item.GetName()] = item
+	}
+
+	homes := &bundleKind{
+		name:    "home",
+		current: make(map[string]proto.Message),
+		wanted:  make(map[string]proto.Message),
+		put: func(item proto.Message) error {
+			home := item.(*pb.Home)
+			return store.PutHome(ctx, home.GetName(), home.GetEmpty())
+		},
+		remove: func(id string) error { return store.DeleteHome(ctx, id) },
+	}
+	houses, err := store.ListHomes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for key, empty := range houses {
+		homes.current[key] = &pb.Home{Name: key, Empty: empty}
+	}
+	for _, item := range bundle.GetHomes() {
+		homes.wanted[item.GetName()] = &pb.Home{Name: item.GetName(), Empty: item.GetEmpty()}
+	}
+
+	commands := &bundleKind{
+		name:    "command",
+		current: make(map[string]proto.Message),
+		wanted:  make(map[string]proto.Message),
+		put:     func(item proto.Message) error { return store.PutTimedCommand(ctx, item.(*pb.TimedCommands)) },
+		remove:  func(id string) error { return store.DeleteTimedCommand(ctx, id) },
+	}
+	tcs, err := store.ListTimedCommands(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for key, item := range tcs {
+		commands.current[key] = item
+	}
+	for _, item := range bundle.GetCommands() {
+		commands.wanted[item.GetId()] = item
+	}
+	return []*bundleKind{devices, bles, people, homes, commands}, nil
+}
+
+// importDevice writes device and leases it for the rest of its away timeout when it is home,
+// the lease watcher marks it away once that runs out
+func importDevice(ctx context.Context, store Store, device *pb.Devices) error {
+	err := store.PutDevice(ctx, device)
+	if err != nil || device.GetAway() {
+		return err
+	}
+	path := "device"
+	if device.GetPerson() {
+		path = "person"
+	}
+	ttl := awayTimeout(device) - (time.Now().Unix() - device.GetLastSeen())
+	if ttl < 1 {
+		ttl = 1
+	}
+	return store.GrantLease(ctx, device.GetHome(), device.GetId().GetUUID(), path, ttl)
+}
+
+// ImportBundle writes the records of bundle to store and returns the changes as
+// "+ kind id" for additions, "~ kind id: field old -> new, ..." for updates and "- kind id" for deletions.
+// Records missing from the bundle are kept unless replace is set, on a dry run
+// the changes are only returned. Devices at home are leased and deleted devices
+// lose their alive key, a running server exports their metrics from the lease events
+func ImportBundle(ctx context.Context, store Store, bundle *pb.Bundle, replace, dryRun bool) ([]string, error) {
+	if bundle.GetVersion() != bundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", bundle.GetVersion())
+	}
+	kinds, err := bundleKinds(ctx, store, bundle)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]string, 0)
+	for _, kind := range kinds {
+		for _, id := range sortedKeys(kind.wanted) {
+			if id == "" {
+				return changes, fmt.Errorf("%s without an id in the bundle", kind.name)
+			}
+			item := kind.wanted[id]
+			current, ok := kind.current[id]
+			if ok && proto.Equal(current, item) {
+				continue
+			}
+			change := fmt.Sprintf("+ %s %s", kind.name, id)
+			if ok {
+				diff, err := fieldDiff(current, item)
+				if err != nil {
+					return changes, err
+				}
+				change = fmt.Sprintf("~ %s %s: %s", kind.name, id, strings.Join(diff, ", "))
+			}
+			changes = append(changes, change)
+			if dryRun {
+				continue
+			}
+			if err := kind.put(item); err != nil {
+				return changes, err
+			}
+		}
+		if !replace {
+			continue
+		}
+		for _, id := range sortedKeys(kind.current) {
+			if _, ok := kind.wanted[id]; ok {
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("- %s %s", kind.name, id))
+			if dryRun {
+				continue
+			}
+			if err := kind.remove(id); err != nil {
+				return changes, err
+			}
+		}
+	}
+	return changes, nil
+}
+
+// fieldDiff returns the JSON fields that differ between current and wanted as "field old -> new",
+// null for a field left unset
+func fieldDiff(current, wanted proto.Message) ([]string, error) {
+	fields := make([]map[string]json.RawMessage, 0, 2)
+	for _, msg := range []proto.Message{current, wanted} {
+		data, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		fields = append(fields, values)
+	}
+	names := make(map[string]bool)
+	for _, values := range fields {
+		for name := range values {
+			names[name] = true
+		}
+	}
+	diff := make([]string, 0)
+	for _, name := range sortedKeys(names) {
+		before, after := compactJSON(fields[0][name]), compactJSON(fields[1][name])
+		if before != after {
+			diff = append(diff, fmt.Sprintf("%s %s -> %s", name, before, after))
+		}
+	}
+	return diff, nil
+}
+
+// compactJSON returns value without whitespace, null when it is missing
+func compactJSON(value json.RawMessage) string {
+	if value == nil {
+		return "null"
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, value); err != nil {
+		return string(value)
+	}
+	return buf.String()
+}
+
+// MarshalBundle encodes bundle as json or yaml with the protobuf JSON field names
+func MarshalBundle(bundle *pb.Bundle, format string) ([]byte, error) {
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "json":
+		return data, nil
+	case "yaml":
+		// JSON is YAML, a MapSlice keeps the field order
+		var doc yaml.MapSlice
+		err = yaml.Unmarshal(data, &doc)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(doc)
+	}
+	return nil, fmt.Errorf("unknown bundle format: %s", format)
+}
+
+// UnmarshalBundle decodes a json or yaml bundle written by MarshalBundle
+func UnmarshalBundle(data []byte) (*pb.Bundle, error) {
+	var doc interface{}
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	js, err := json.Marshal(jsonValue(doc))
+	if err != nil {
+		return nil, err
+	}
+	bundle := &pb.Bundle{}
+	err = protojson.Unmarshal(js, bundle)
+	if err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// jsonValue converts the map[interface{}]interface{} yaml decodes objects to into
+// map[string]interface{} so the document can be encoded as JSON
+func jsonValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			result[fmt.Sprint(key)] = jsonValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range value {
+			value[i] = jsonValue(item)
+		}
+	}
+	return value
+}
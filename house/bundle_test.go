@@ -0,0 +1,101 @@
+package house
+
+import (
+	"context"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/protobuf/proto"
+	"testing"
+)
+
+func TestBundleImportExport(t *testing.T) {
+	source := newTestHarness(t)
+	ctx := context.Background()
+	phone := &pb.Devices{Id: &pb.NetworkId{Mac: "AA:BB:CC:DD:EE:20", UUID: "AA:BB:CC:DD:EE:20"}, Home: "aus", Name: "phone", Person: true}
+	if err := source.Store.PutDevice(ctx, phone); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Store.PutBleDevice(ctx, &pb.BleDevices{Id: "tile", Name: "keys", Tile: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Store.PutPerson(ctx, &pb.People{Name: "sam", Ids: []string{"AA:BB:CC:DD:EE:20", "tile"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Store.PutHome(ctx, "aus", false); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Store.PutTimedCommand(ctx, &pb.TimedCommands{Id: "lights", Command: "lights off", Executeat: 4102444800}); err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := ExportBundle(ctx, source.Store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{"yaml", "json"} {
+		data, err := MarshalBundle(bundle, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := UnmarshalBundle(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(bundle, decoded) {
+			t.Errorf("%s: expected the bundle to round trip, got %v", format, decoded)
+		}
+	}
+
+	target := newTestHarness(t)
+	stale := &pb.Devices{Id: &pb.NetworkId{Mac: "AA:BB:CC:DD:EE:21", UUID: "AA:BB:CC:DD:EE:21"}, Home: "nz"}
+	if err := target.Store.PutDevice(ctx, stale); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.Store.GrantLease(ctx, "nz", "AA:BB:CC:DD:EE:21", "device", 300); err != nil {
+		t.Fatal(err)
+	}
+	target.RegisterMetric(stale)
+	changes, err := ImportBundle(ctx, target.Store, bundle, true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[+ device AA:BB:CC:DD:EE:20 - device AA:BB:CC:DD:EE:21 + ble tile + person sam + home aus + command lights]"
+	if fmt.Sprint(changes) != expected {
+		t.Errorf("expected %s, got %v", expected, changes)
+	}
+	if device, _ := target.Store.GetDevice(ctx, "AA:BB:CC:DD:EE:20"); device != nil {
+		t.Error("expected a dry run to leave the store untouched")
+	}
+	if _, err := ImportBundle(ctx, target.Store, bundle, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if device, _ := target.Store.GetDevice(ctx, "AA:BB:CC:DD:EE:21"); device == nil {
+		t.Error("expected a merge to keep the devices missing from the bundle")
+	}
+	if alive, err := target.Store.ListAlive(ctx, "aus"); err != nil || alive["aus/AA:BB:CC:DD:EE:20"] != "person" {
+		t.Errorf("expected the imported phone to be leased, got %v %v", alive, err)
+	}
+	eventually(t, func() bool { return target.hasGauge("AA:BB:CC:DD:EE:20") }, "expected the imported phone to be exported")
+	if changes, _ := ImportBundle(ctx, target.Store, bundle, false, true); len(changes) != 0 {
+		t.Errorf("expected nothing left to merge, got %v", changes)
+	}
+	renamed := proto.Clone(bundle.GetDevices()[0]).(*pb.Devices)
+	renamed.Name, renamed.Person = "laptop", false
+	if err := target.Store.PutDevice(ctx, renamed); err != nil {
+		t.Fatal(err)
+	}
+	changes, err = ImportBundle(ctx, target.Store, bundle, false, true)
+	expected = `[~ device AA:BB:CC:DD:EE:20: Name "laptop" -> "phone", Person null -> true]`
+	if err != nil || fmt.Sprint(changes) != expected {
+		t.Errorf("expected %s, got %v %v", expected, changes, err)
+	}
+	if _, err := ImportBundle(ctx, target.Store, bundle, true, false); err != nil {
+		t.Fatal(err)
+	}
+	if device, _ := target.Store.GetDevice(ctx, "AA:BB:CC:DD:EE:21"); device != nil {
+		t.Error("expected a replace to delete the devices missing from the bundle")
+	}
+	if alive, err := target.Store.ListAlive(ctx, "nz"); err != nil || len(alive) != 0 {
+		t.Errorf("expected the deleted device to lose its alive key, got %v %v", alive, err)
+	}
+	eventually(t, func() bool { return !target.hasGauge("AA:BB:CC:DD:EE:21") }, "expected the deleted device to no longer be exported")
+}
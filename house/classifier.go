@@ -0,0 +1,135 @@
+package house
+
+import (
+	_ "embed"
+	"flag"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"gopkg.in/yaml.v2"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var categoryRulesFile = flag.String("categoryRules", "", "Path to the device category rules, leave blank for the built in rules")
+
+// defaultCategoryRules are used without -categoryRules
+//
+//go:embed categories.yaml
+var defaultCategoryRules []byte
+
+// CategoryRule assigns its category to the devices matching any of its vendors, hostnames, ports or services
+type CategoryRule struct {
+	Category string `yaml:"category"`
+	// Vendors are case insensitive substrings of the Manufacturer
+	Vendors []string `yaml:"vendors"`
+	// Hostnames are case insensitive regular expressions
+	Hostnames []string `yaml:"hostnames"`
+	Ports     []int32  `yaml:"ports"`
+	// Services are mDNS service types eg _googlecast._tcp
+	Services []string `yaml:"services"`
+	// Person and PresenceAware are the defaults for new devices, nil leaves them alone
+	Person        *bool `yaml:"person"`
+	PresenceAware *bool `yaml:"presenceAware"`
+
+	hostnames []*regexp.Regexp
+}
+
+// Classifier assigns the category of the first rule matching a device
+type Classifier struct {
+	rules []*CategoryRule
+}
+
+// NewClassifier returns a Classifier for rules in order
+func NewClassifier(rules []*CategoryRule) (*Classifier, error) {
+	for i, rule := range rules {
+		if rule.Category == "" {
+			return nil, fmt.Errorf("category rule %d has no category", i+1)
+		}
+		if len(rule.Vendors)+len(rule.Hostnames)+len(rule.Ports)+len(rule.Services) == 0 {
+			return nil, fmt.Errorf("category rule %s matches nothing", rule.Category)
+		}
+		rule.hostnames = make([]*regexp.Regexp, 0, len(rule.Hostnames))
+		for _, pattern := range rule.Hostnames {
+			re, err := regexp.Compile("(?i)" + pattern)
+			if err != nil {
+				return nil, fmt.Errorf("category rule %s: %w", rule.Category, err)
+			}
+			rule.hostnames = append(rule.hostnames, re)
+		}
+	}
+	return &Classifier{rules: rules}, nil
+}
+
+// ParseClassifier returns the Classifier for the YAML list of rules in data
+func ParseClassifier(data []byte) (*Classifier, error) {
+	rules := make([]*CategoryRule, 0)
+	err := yaml.UnmarshalStrict(data, &rules)
+	if err != nil {
+		return nil, err
+	}
+	return NewClassifier(rules)
+}
+
+// ReadClassifier returns the Classifier for the -categoryRules file or the built in rules when the flag is blank
+func ReadClassifier() (*Classifier, error) {
+	if *categoryRulesFile == "" {
+		return ParseClassifier(defaultCategoryRules)
+	}
+	data, err := os.ReadFile(*categoryRulesFile)
+	if err != nil {
+		return nil, err
+	}
+	return ParseClassifier(data)
+}
+
+// matches reports whether any of the vendors, hostnames, ports or services of the rule match device
+func (rule *CategoryRule) matches(device *pb.Devices) bool {
+	vendor := strings.ToLower(device.GetManufacturer())
+	if knownVendor(vendor) {
+		for _, match := range rule.Vendors {
+			if strings.Contains(vendor, strings.ToLower(match)) {
+				return true
+			}
+		}
+	}
+	for _, re := range rule.hostnames {
+		if slices.ContainsFunc(device.GetHostnames(), re.MatchString) {
+			return true
+		}
+	}
+	for _, port := range device.GetOpenPorts() {
+		if slices.Contains(rule.Ports, port.GetPort()) {
+			return true
+		}
+	}
+	for _, service := range device.GetServices() {
+		if slices.Contains(rule.Services, service) {
+			return true
+		}
+	}
+	return false
+}
+
+// Classify returns the first rule matching device or nil when none do
+func (c *Classifier) Classify(device *pb.Devices) *CategoryRule {
+	for _, rule := range c.rules {
+		if rule.matches(device) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// classify sets the category of a device without one and returns the rule that matched it, if any
+func (s *Server) classify(device *pb.Devices) *CategoryRule {
+	if s.classifier == nil || device.GetCategory() != "" {
+		return nil
+	}
+	rule := s.classifier.Classify(device)
+	if rule != nil {
+		device.Category = rule.Category
+	}
+	return rule
+}
@@ -0,0 +1,52 @@
+package house
+
+import (
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"testing"
+)
+
+func TestClassifier(t *testing.T) {
+	classifier, err := ReadClassifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		device   *pb.Devices
+		category string
+	}{
+		{&pb.Devices{Hostnames: []string{"Sams-iPhone.lan"}}, "phone"},
+		{&pb.Devices{Manufacturer: "Brother Industries, LTD."}, "printer"},
+		{&pb.Devices{OpenPorts: []*pb.OpenPort{{Port: 9100, Protocol: "tcp"}}}, "printer"},
+		{&pb.Devices{Services: []string{"_googlecast._tcp"}}, "tv"},
+		{&pb.Devices{Manufacturer: "Google, Inc.", Hostnames: []string{"chromecast.lan"}}, "tv"},
+		{&pb.Devices{Manufacturer: "Google, Inc.", Hostnames: []string{"pixel-7.lan"}}, "phone"},
+		{&pb.Devices{Manufacturer: "Amazon Technologies Inc."}, ""},
+		{&pb.Devices{Manufacturer: "Espressif Inc."}, "iot"},
+		{&pb.Devices{Manufacturer: "unknown", Hostnames: []string{"mystery.lan"}}, ""},
+	}
+	for _, tt := range tests {
+		category := ""
+		if rule := classifier.Classify(tt.device); rule != nil {
+			category = rule.Category
+		}
+		if category != tt.category {
+			t.Errorf("%v: expected %q, got %q", tt.device, tt.category, category)
+		}
+	}
+	for _, rules := range []string{"- category: tv\n  hostnames: ['(']", "- category: tv", "- hostnames: [tv]", "- category: tv\n  vendor: [roku]"} {
+		if _, err := ParseClassifier([]byte(rules)); err == nil {
+			t.Errorf("expected %q to be rejected", rules)
+		}
+	}
+
+	h := newTestHarness(t)
+	in := &pb.AddressRequest{Ip: "10.0.2.1", Mac: "A8:BB:CC:DD:EE:20", Vendor: "Apple", Hosts: []string{"Sams-iPhone.lan"}}
+	h.report(t, "aus", in)
+	device, err := h.GetDevice("A8:BB:CC:DD:EE:20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetCategory() != "phone" || !device.GetPerson() || !device.GetPresenceAware() {
+		t.Errorf("expected a phone tracked as a person, got %v", device)
+	}
+}
@@ -0,0 +1,41 @@
+package house
+
+import (
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"testing"
+	"time"
+)
+
+func TestProcessTimedCommandQueue(t *testing.T) {
+	h := newTestHarness(t)
+	enabled := *cqEnabled
+	*cqEnabled = true
+	defer func() { *cqEnabled = enabled }()
+
+	due := &pb.TimedCommands{Id: "due", Owner: "aus", Command: "Turn TV off", Executeat: time.Now().Unix() - 1}
+	later := &pb.TimedCommands{Id: "later", Owner: "aus", Command: "Turn lights off", Executeat: time.Now().Unix() + 3600}
+	for _, tc := range []*pb.TimedCommands{due, later} {
+		if err := h.writeTc(tc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.processTimedCommandQueue(); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.assistant.commands) != 1 || h.assistant.commands[0] != "Turn TV off" {
+		t.Errorf("expected only the due command to run, got %v", h.assistant.commands)
+	}
+	tcs, err := h.getTc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tcs["due"]; ok {
+		t.Error("expected executed command to be removed")
+	}
+	if _, ok := tcs["later"]; !ok {
+		t.Error("expected pending command to remain")
+	}
+	if !h.notifier.contains("Scheduled Task|Turn TV off") {
+		t.Errorf("expected scheduled task notification, got %v", h.notifier.sent)
+	}
+}
@@ -0,0 +1,22 @@
+package house
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// Dashboard returns the handler serving the web dashboard, it reads and changes everything
+// through the /api/v1 REST API and reloads on the /events stream so expects to be mounted
+// at a path next to them such as /dashboard/
+func Dashboard() http.Handler {
+	files, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		// the directory is embedded so this can only be a programming error
+		panic(err)
+	}
+	return http.FileServer(http.FS(files))
+}
@@ -0,0 +1,227 @@
+'use strict';
+
+const api = '../api/v1/';
+const state = { homes: [], people: [], devices: [], ble: [], commands: [] };
+
+const keyInput = document.getElementById('apikey');
+keyInput.value = localStorage.getItem('apikey') || '';
+keyInput.addEventListener('change', () => localStorage.setItem('apikey', keyInput.value));
+document.getElementById('filter').addEventListener('input', renderDevices);
+
+function setStatus(message, error) {
+  const status = document.getElementById('status');
+  status.textContent = message;
+  status.className = error ? 'error' : '';
+}
+
+async function request(method, path, body) {
+  const headers = { 'Content-Type': 'application/json' };
+  if (keyInput.value) {
+    headers.apikey = keyInput.value;
+  }
+  const response = await fetch(api + path, {
+    method: method,
+    headers: headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (response.status === 204) {
+    return null;
+  }
+  const data = await response.json();
+  if (!response.ok) {
+    throw new Error(data.error || response.statusText);
+  }
+  return data;
+}
+
+// change runs a mutation then reloads everything so the page matches the server
+async function change(method, path, body) {
+  try {
+    await request(method, path, body);
+    await load();
+  } catch (err) {
+    setStatus(err.message, true);
+  }
+}
+
+function element(tag, text, className) {
+  const node = document.createElement(tag);
+  if (text !== undefined) {
+    node.textContent = text;
+  }
+  if (className) {
+    node.className = className;
+  }
+  return node;
+}
+
+function button(label, onClick) {
+  const node = element('button', label);
+  node.addEventListener('click', onClick);
+  return node;
+}
+
+function age(seconds) {
+  if (!seconds || seconds === '0') {
+    return 'never';
+  }
+  const elapsed = Math.max(0, Math.floor(Date.now() / 1000) - Number(seconds));
+  if (elapsed < 60) {
+    return elapsed + 's ago';
+  }
+  if (elapsed < 3600) {
+    return Math.floor(elapsed / 60) + 'm ago';
+  }
+  if (elapsed < 86400) {
+    return Math.floor(elapsed / 3600) + 'h ago';
+  }
+  return Math.floor(elapsed / 86400) + 'd ago';
+}
+
+function uuid(device) {
+  return (device.Id && device.Id.UUID) || '';
+}
+
+function deviceName(id) {
+  const device = state.devices.find((item) => uuid(item) === id);
+  if (device) {
+    return device.Name || id;
+  }
+  const ble = state.ble.find((item) => item.Id === id);
+  return (ble && ble.Name) || id;
+}
+
+function personCard(title, status, className, people) {
+  const card = element('div', undefined, 'card');
+  card.append(element('h3', title));
+  card.append(element('div', status, className));
+  const list = element('ul');
+  for (const person of people) {
+    const item = element('li', person.Name);
+    const devices = element('ul');
+    for (const id of person.ids) {
+      devices.append(element('li', deviceName(id)));
+    }
+    item.append(devices);
+    list.append(item);
+  }
+  card.append(list);
+  return card;
+}
+
+function renderHomes() {
+  const homes = document.getElementById('homes');
+  homes.replaceChildren();
+  for (const home of state.homes) {
+    const people = state.people.filter((person) => !person.away && person.home === home.name);
+    homes.append(personCard(home.name, home.empty ? 'Empty' : 'Occupied', home.empty ? 'empty' : 'occupied', people));
+  }
+  const away = state.people.filter((person) => person.away);
+  if (away.length > 0) {
+    homes.append(personCard('Away', away.length + ' away', 'away', away));
+  }
+}
+
+function renderDevices() {
+  const filter = document.getElementById('filter').value.toLowerCase();
+  const rows = document.getElementById('devices');
+  rows.replaceChildren();
+  for (const device of state.devices) {
+    const id = uuid(device);
+    const text = [device.Name, device.Home, id, device.Manufacturer].concat(device.Hostnames).join(' ').toLowerCase();
+    if (filter && !text.includes(filter)) {
+      continue;
+    }
+    const path = 'devices/' + encodeURIComponent(id);
+    const row = element('tr', undefined, device.Away ? 'away' : '');
+    row.append(
+      element('td', device.Name || id),
+      element('td', device.Home),
+      element('td', device.Id ? device.Id.Ip : ''),
+      element('td', device.Id ? device.Id.Mac + (device.Randomized ? ' (randomized)' : '') : ''),
+      element('td', device.Manufacturer),
+      element('td', device.Hostnames.join(', ')),
+      element('td', age(device.LastSeen)),
+      element('td', device.Away ? 'yes' : 'no'),
+      element('td', device.Person ? 'yes' : 'no'),
+      element('td', device.PresenceAware ? 'yes' : 'no'),
+    );
+    const actions = element('td');
+    actions.append(
+      button('Rename', () => {
+        const name = prompt('Name for ' + id, device.Name);
+        if (name !== null) {
+          change('PATCH', path, { Name: name });
+        }
+      }),
+      button(device.Person ? 'Not a person' : 'Person', () => change('PATCH', path, { Person: !device.Person })),
+      button(device.PresenceAware ? 'Not presence aware' : 'Presence aware',
+        () => change('PATCH', path, { PresenceAware: !device.PresenceAware })),
+      button('Delete', () => {
+        if (confirm('Delete ' + (device.Name || id) + '?')) {
+          change('DELETE', path);
+        }
+      }),
+    );
+    row.append(actions);
+    rows.append(row);
+  }
+}
+
+function renderCommands() {
+  const rows = document.getElementById('commands');
+  rows.replaceChildren();
+  for (const command of state.commands) {
+    const path = 'commands/' + encodeURIComponent(command.id);
+    const row = element('tr');
+    row.append(
+      element('td', command.id),
+      element('td', command.owner),
+      element('td', command.command),
+      element('td', new Date(Number(command.executeat) * 1000).toLocaleString()),
+    );
+    const actions = element('td');
+    actions.append(
+      button('Run now', () => change('POST', path + '/complete')),
+      button('Cancel', () => change('DELETE', path)),
+    );
+    row.append(actions);
+    rows.append(row);
+  }
+}
+
+async function load() {
+  try {
+    const [homes, people, devices, ble, commands] = await Promise.all([
+      request('GET', 'homes'),
+      request('GET', 'people'),
+      request('GET', 'devices'),
+      request('GET', 'ble'),
+      request('GET', 'commands'),
+    ]);
+    state.homes = homes.homes;
+    state.people = people.people;
+    state.devices = devices.devices.sort((a, b) => (a.Name || uuid(a)).localeCompare(b.Name || uuid(b)));
+    state.ble = ble.bles;
+    state.commands = commands.cqs;
+    renderHomes();
+    renderDevices();
+    renderCommands();
+    setStatus('Updated ' + new Date().toLocaleTimeString(), false);
+  } catch (err) {
+    setStatus(err.message, true);
+  }
+}
+
+// reload whenever a presence event arrives rather than polling
+let pending;
+const events = new EventSource('../events' + (keyInput.value ? '?apikey=' + encodeURIComponent(keyInput.value) : ''));
+for (const type of ['DEVICE_ARRIVED', 'DEVICE_LEFT', 'DEVICE_MOVED_HOME', 'PERSON_ARRIVED', 'PERSON_LEFT',
+  'HOUSE_EMPTY_CHANGED', 'NEW_DEVICE_DISCOVERED']) {
+  events.addEventListener(type, () => {
+    clearTimeout(pending);
+    pending = setTimeout(load, 500);
+  });
+}
+setInterval(renderDevices, 30000);
+load();
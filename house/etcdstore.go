@@ -0,0 +1,533 @@
+package house
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	etcdv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/protobuf/proto"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Leaser grants and looks up the etcd leases backing the alive keys
+type Leaser interface {
+	GrantLease(ctx context.Context, path, mac string, ttl int64) (string, *etcdv3.LeaseID, error)
+	DeleteLeaseByKey(ctx context.Context, key string) error
+	GetLeaseByKey(ctx context.Context, key string) (*etcdv3.LeaseStatus, *etcdv3.LeaseTimeToLiveResponse, error)
+	Grant(ctx context.Context, ttl int64) (*etcdv3.LeaseGrantResponse, error)
+}
+
+// EtcdLeaser is an implementation of the Leaser, the lease of an alive key is
+// read from the key itself so lookups are a single Get rather than a scan of every lease
+type EtcdLeaser struct {
+	etcdv3.Lease
+	Kv etcdv3.KV
+}
+
+// leaseOf returns the id of the lease attached to key or etcdv3.NoLease
+func (leaser *EtcdLeaser) leaseOf(ctx context.Context, key string) (etcdv3.LeaseID, error) {
+	items, err := leaser.Kv.Get(ctx, key)
+	if err != nil {
+		return etcdv3.NoLease, err
+	}
+	if items == nil || len(items.Kvs) == 0 {
+		return etcdv3.NoLease, nil
+	}
+	return etcdv3.LeaseID(items.Kvs[0].Lease), nil
+}
+
+func (leaser *EtcdLeaser) GetLeaseByKey(ctx context.Context, key string) (*etcdv3.LeaseStatus, *etcdv3.LeaseTimeToLiveResponse, error) {
+	id, err := leaser.leaseOf(ctx, key)
+	if err != nil || id == etcdv3.NoLease {
+		return nil, nil, err
+	}
+	leaseTTL, err := leaser.TimeToLive(ctx, id, etcdv3.WithAttachedKeys())
+	if err != nil {
+		return nil, nil, err
+	}
+	if leaseTTL.TTL < 0 {
+		return nil, nil, nil
+	}
+	return &etcdv3.LeaseStatus{ID: id}, leaseTTL, nil
+}
+
+func (leaser *EtcdLeaser) DeleteLeaseByKey(ctx context.Context, key string) error {
+	id, err := leaser.leaseOf(ctx, key)
+	if err != nil || id == etcdv3.NoLease {
+		return err
+	}
+	_, err = leaser.Revoke(ctx, id)
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return nil
+	}
+	return err
+}
+
+func NewEtcdLeaser(lease etcdv3.Lease, kv etcdv3.KV) Leaser {
+	return &EtcdLeaser{Lease: lease, Kv: kv}
+}
+
+// GrantLease refreshes the lease of the alive key for mac, a new lease is only
+// granted (and its key returned for the caller to put) when there is no live lease
+// or it was granted for another ttl, the old lease expires with nothing attached
+func (leaser *EtcdLeaser) GrantLease(ctx context.Context, path, mac string, ttl int64) (string, *etcdv3.LeaseID, error) {
+	keyPath := filepath.Join(AlivePrefix, path, mac)
+	id, err := leaser.leaseOf(ctx, keyPath)
+	if err != nil {
+		return "", nil, err
+	}
+	if id != etcdv3.NoLease {
+		current, err := leaser.TimeToLive(ctx, id)
+		if err != nil {
+			return "", nil, err
+		}
+		if current.TTL >= 0 && current.GrantedTTL == ttl {
+			_, err = leaser.KeepAliveOnce(ctx, id)
+			if err == nil {
+				return "", nil, nil
+			}
+			// the lease expired between the get and the keep alive
+			if !errors.Is(err, rpctypes.ErrLeaseNotFound) {
+				return "", nil, err
+			}
+		}
+	}
+	lease, err := leaser.Grant(ctx, ttl)
+	if err != nil {
+		return "", nil, err
+	}
+	return keyPath, &lease.ID, nil
+}
+
+// EtcdStore is an implementation of the Store backed by an etcd cluster
+type EtcdStore struct {
+	Kv      etcdv3.KV
+	Leaser  Leaser
+	Watcher etcdv3.Watcher
+}
+
+// NewEtcdStore returns a Store using kv for records and leaser and watcher for presence
+func NewEtcdStore(kv etcdv3.KV, leaser Leaser, watcher etcdv3.Watcher) Store {
+	return &EtcdStore{Kv: kv, Leaser: leaser, Watcher: watcher}
+}
+
+func (e *EtcdStore) put(ctx context.Context, key string, item proto.Message) error {
+	d1, err := marshalRecord(item)
+	if err != nil {
+		return err
+	}
+	_, err = e.Kv.Put(ctx, key, string(d1))
+	return err
+}
+
+// list returns the raw values under prefix keyed without prefix
+func (e *EtcdStore) list(ctx context.Context, prefix string) (map[string][]byte, error) {
+	result := make(map[string][]byte)
+	items, err := e.Kv.Get(ctx, prefix, etcdv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		return result, nil
+	}
+	for _, kv := range items.Kvs {
+		result[strings.TrimPrefix(string(kv.Key), prefix)] = kv.Value
+	}
+	return result, nil
+}
+
+func (e *EtcdStore) GetDevice(ctx context.Context, id string) (*pb.Devices, error) {
+	items, err := e.Kv.Get(ctx, fmt.Sprintf("%s%s", devicesPrefix, id))
+	if err != nil {
+		return nil, err
+	}
+	if items == nil || items.Count == 0 {
+		return nil, nil
+	}
+	dev := &pb.Devices{}
+	err = unmarshalRecord(items.Kvs[0].Value, dev)
+	if err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+func (e *EtcdStore) ListDevices(ctx context.Context) (map[string]*pb.Devices, error) {
+	items, err := e.list(ctx, devicesPrefix)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*pb.Devices)
+	for key, val := range items {
+		dev := &pb.Devices{}
+		err = unmarshalRecord(val, dev)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = dev
+	}
+	return result, nil
+}
+
+func (e *EtcdStore) ScanDevices(ctx context.Context, after string, limit int64) ([]string, []*pb.Devices, bool, error) {
+	start := devicesPrefix
+	if after != "" {
+		// the smallest key greater than the last device
+		start = fmt.Sprintf("%s%s\x00", devicesPrefix, after)
+	}
+	items, err := e.Kv.Get(ctx, start, etcdv3.WithRange(etcdv3.GetPrefixRangeEnd(devicesPrefix)), etcdv3.WithLimit(limit))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	keys := make([]string, 0, len(items.Kvs))
+	result := make([]*pb.Devices, 0, len(items.Kvs))
+	for _, kv := range items.Kvs {
+		dev := &pb.Devices{}
+		err = unmarshalRecord(kv.Value, dev)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		keys = append(keys, strings.TrimPrefix(string(kv.Key), devicesPrefix))
+		result = append(result, dev)
+	}
+	return keys, result, items.More, nil
+}
+
+func (e *EtcdStore) PutDevice(ctx context.Context, item *pb.Devices) error {
+	err := e.put(ctx, fmt.Sprintf("%s%s", devicesPrefix, item.Id.UUID), item)
+	if err != nil {
+		return err
+	}
+	for _, key := range indexKeys(item) {
+		_, err = e.Kv.Put(ctx, fmt.Sprintf("%s%s", indexPrefix, key), item.Id.UUID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *EtcdStore) DeleteDevice(ctx context.Context, id string) error {
+	_, err := e.Kv.Delete(ctx, fmt.Sprintf("%s%s", devicesPrefix, id))
+	return err
+}
+
+func (e *EtcdStore) FindDevices(ctx context.Context, home, ip string, hostnames []string) ([]*pb.Devices, error) {
+	result := make([]*pb.Devices, 0)
+	seen := make(map[string]bool)
+	for _, key := range findKeys(home, ip, hostnames) {
+		items, err := e.Kv.Get(ctx, fmt.Sprintf("%s%s", indexPrefix, key))
+		if err != nil {
+			return nil, err
+		}
+		if items == nil || items.Count == 0 || seen[string(items.Kvs[0].Value)] {
+			continue
+		}
+		seen[string(items.Kvs[0].Value)] = true
+		device, err := e.GetDevice(ctx, string(items.Kvs[0].Value))
+		if err != nil {
+			return nil, err
+		}
+		if device != nil {
+			result = append(result, device)
+		}
+	}
+	return result, nil
+}
+
+func (e *EtcdStore) GetBleDevice(ctx context.Context, id string) (*pb.BleDevices, error) {
+	items, err := e.Kv.Get(ctx, fmt.Sprintf("%s%s", BlesPrefix, id), etcdv3.WithLimit(1))
+	if err != nil {
+		return nil, err
+	}
+	if items == nil || items.Count != 1 {
+		return nil, nil
+	}
+	device := &pb.BleDevices{}
+	err = unmarshalRecord(items.Kvs[0].Value, device)
+	if err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+func (e *EtcdStore) ListBleDevices(ctx context.Context) (map[string]*pb.BleDevices, error) {
+	items, err := e.list(ctx, BlesPrefix)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*pb.BleDevices)
+	for key, val := range items {
+		dev := &pb.BleDevices{}
+		err = unmarshalRecord(val, dev)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = dev
+	}
+	return result, nil
+}
+
+func (e *EtcdStore) PutBleDevice(ctx context.Context, item *pb.BleDevices) error {
+	return e.put(ctx, fmt.Sprintf("%s%s", BlesPrefix, item.Id), item)
+}
+
+func (e *EtcdStore) DeleteBleDevice(ctx context.Context, id string) error {
+	_, err := e.Kv.Delete(ctx, fmt.Sprintf("%s%s", BlesPrefix, id))
+	return err
+}
+
+func (e *EtcdStore) ListTimedCommands(ctx context.Context, prefix string) (map[string]*pb.TimedCommands, error) {
+	items, err := e.list(ctx, fmt.Sprintf("%s%s", tcPrefix, prefix))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*pb.TimedCommands)
+	for key, val := range items {
+		tc := &pb.TimedCommands{}
+		err = unmarshalRecord(val, tc)
+		if err != nil {
+			return nil, err
+		}
+		result[prefix+key] = tc
+	}
+	return result, nil
+}
+
+func (e *EtcdStore) PutTimedCommand(ctx context.Context, item *pb.TimedCommands) error {
+	return e.put(ctx, fmt.Sprintf("%s%s", tcPrefix, item.Id), item)
+}
+
+func (e *EtcdStore) DeleteTimedCommand(ctx context.Context, id string) error {
+	_, err := e.Kv.Delete(ctx, fmt.Sprintf("%s%s", tcPrefix, id))
+	return err
+}
+
+func (e *EtcdStore) DeleteTimedCommands(ctx context.Context, prefix string) error {
+	_, err := e.Kv.Delete(ctx, fmt.Sprintf("%s%s", tcPrefix, prefix), etcdv3.WithPrefix())
+	return err
+}
+
+func (e *EtcdStore) GetPerson(ctx context.Context, name string) (*pb.People, error) {
+	items, err := e.Kv.Get(ctx, fmt.Sprintf("%s%s", peoplePrefix, name))
+	if err != nil {
+		return nil, err
+	}
+	if items == nil || items.Count == 0 {
+		return nil, nil
+	}
+	human := &pb.People{}
+	err = unmarshalRecord(items.Kvs[0].Value, human)
+	if err != nil {
+		return nil, err
+	}
+	return human, nil
+}
+
+func (e *EtcdStore) ListPeople(ctx context.Context) (map[string]*pb.People, error) {
+	items, err := e.list(ctx, peoplePrefix)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*pb.People)
+	for key, val := range items {
+		human := &pb.People{}
+		err = unmarshalRecord(val, human)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = human
+	}
+	return result, nil
+}
+
+func (e *EtcdStore) PutPerson(ctx context.Context, person *pb.People) error {
+	return e.put(ctx, fmt.Sprintf("%s%s", peoplePrefix, person.Name), person)
+}
+
+func (e *EtcdStore) DeletePerson(ctx context.Context, name string) error {
+	_, err := e.Kv.Delete(ctx, fmt.Sprintf("%s%s", peoplePrefix, name))
+	return err
+}
+
+func (e *EtcdStore) ListHomes(ctx context.Context) (map[string]bool, error) {
+	result := make(map[string]bool)
+	items, err := e.Kv.Get(ctx, HomePrefix, etcdv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		return result, nil
+	}
+	for _, kv := range items.Kvs {
+		key := string(kv.Key)
+		boolVal, _ := strconv.ParseBool(string(kv.Value))
+		// older releases wrote homes as /homes//<home>
+		if strings.Contains(key, "//") {
+			key2 := strings.ReplaceAll(key, "//", "")
+			_, err := e.Kv.Put(ctx, key2, string(kv.Value))
+			if err != nil {
+				return nil, err
+			}
+			_, err = e.Kv.Delete(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result[strings.ReplaceAll(key, HomePrefix, "")] = boolVal
+	}
+	return result, nil
+}
+
+func (e *EtcdStore) PutHome(ctx context.Context, home string, empty bool) error {
+	_, err := e.Kv.Put(ctx, fmt.Sprintf("%s%s", HomePrefix, home), strconv.FormatBool(empty))
+	return err
+}
+
+func (e *EtcdStore) DeleteHome(ctx context.Context, home string) error {
+	_, err := e.Kv.Delete(ctx, fmt.Sprintf("%s%s", HomePrefix, home))
+	return err
+}
+
+func (e *EtcdStore) GrantLease(ctx context.Context, home, mac, value string, ttl int64) error {
+	key, leaseId, err := e.Leaser.GrantLease(ctx, home, mac, ttl)
+	if err != nil {
+		return err
+	}
+	if leaseId != nil && key != "" {
+		_, err = e.Kv.Put(ctx, key, value, etcdv3.WithLease(*leaseId))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *EtcdStore) PutAlive(ctx context.Context, home, mac, value string) error {
+	_, err := e.Kv.Put(ctx, filepath.Join(AlivePrefix, home, mac), value)
+	return err
+}
+
+func (e *EtcdStore) DeleteAlive(ctx context.Context, home, mac string) error {
+	_, err := e.Kv.Delete(ctx, filepath.Join(AlivePrefix, home, mac))
+	return err
+}
+
+func (e *EtcdStore) ListAlive(ctx context.Context, home string) (map[string]string, error) {
+	prefix := alivePrefix(home)
+	items, err := e.list(ctx, fmt.Sprintf("%s%s", AlivePrefix, prefix))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	for key, val := range items {
+		result[prefix+key] = string(val)
+	}
+	return result, nil
+}
+
+// WatchAlive watches the alive keys until ctx is done, a watch that closes or fails
+// is watched again from the revision after the last event
+func (e *EtcdStore) WatchAlive(ctx context.Context) (<-chan AliveEvent, error) {
+	events := make(chan AliveEvent)
+	// the first watch starts before returning so no event after the call is missed
+	watchCtx, cancel := context.WithCancel(ctx)
+	watch := e.Watcher.Watch(watchCtx, AlivePrefix, etcdv3.WithPrefix())
+	go func() {
+		defer close(events)
+		var rev int64
+		for {
+			var err error
+			rev, err = e.watchAlive(ctx, watch, rev, events)
+			cancel()
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("alive watch closed at revision %d, watching again: %v", rev, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			opts := []etcdv3.OpOption{etcdv3.WithPrefix()}
+			if rev > 0 {
+				opts = append(opts, etcdv3.WithRev(rev+1))
+			}
+			watchCtx, cancel = context.WithCancel(ctx)
+			watch = e.Watcher.Watch(watchCtx, AlivePrefix, opts...)
+		}
+	}()
+	return events, nil
+}
+
+// watchAlive sends the alive events of watch until it closes and returns the revision of the last one
+func (e *EtcdStore) watchAlive(ctx context.Context, watch etcdv3.WatchChan, rev int64, events chan<- AliveEvent) (int64, error) {
+	for resp := range watch {
+		if resp.CompactRevision > 0 {
+			// the events after rev were compacted away, carry on from the oldest revision left
+			return resp.CompactRevision - 1, resp.Err()
+		}
+		if err := resp.Err(); err != nil {
+			return rev, err
+		}
+		if len(resp.Events) == 0 && resp.Header.Revision > rev {
+			// progress notifications move the revision on without events
+			rev = resp.Header.Revision
+		}
+		for _, ev := range resp.Events {
+			key := strings.TrimPrefix(string(ev.Kv.Key), AlivePrefix)
+			event := aliveEvent(key, string(ev.Kv.Value), ev.Type == mvccpb.DELETE)
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return rev, ctx.Err()
+			}
+			rev = ev.Kv.ModRevision
+		}
+	}
+	return rev, nil
+}
+
+func (e *EtcdStore) GetVendor(ctx context.Context, oui string) (string, bool, error) {
+	items, err := e.Kv.Get(ctx, fmt.Sprintf("%s%s", vendorsPrefix, oui))
+	if err != nil {
+		return "", false, err
+	}
+	if items == nil || items.Count == 0 {
+		return "", false, nil
+	}
+	return string(items.Kvs[0].Value), true, nil
+}
+
+func (e *EtcdStore) PutVendor(ctx context.Context, oui, vendor string, ttl int64) error {
+	lease, err := e.Leaser.Grant(ctx, ttl)
+	if err != nil {
+		return err
+	}
+	_, err = e.Kv.Put(ctx, fmt.Sprintf("%s%s", vendorsPrefix, oui), vendor, etcdv3.WithLease(lease.ID))
+	return err
+}
+
+func (e *EtcdStore) GetLastNotification(ctx context.Context) (*string, error) {
+	items, err := e.Kv.Get(ctx, fmt.Sprintf("%s%s", notificationsPrefix, "last"))
+	if err != nil {
+		return nil, err
+	}
+	if items == nil || items.Count == 0 {
+		return nil, nil
+	}
+	lastMessage := string(items.Kvs[0].Value)
+	return &lastMessage, nil
+}
+
+func (e *EtcdStore) PutLastNotification(ctx context.Context, notification string) error {
+	_, err := e.Kv.Put(ctx, fmt.Sprintf("%s%s", notificationsPrefix, "last"), notification)
+	return err
+}
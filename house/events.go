@@ -0,0 +1,115 @@
+package house
+
+import (
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/protobuf/proto"
+	"sync"
+	"time"
+)
+
+// eventHistory is how many events are kept for watchers resuming from a lastEventId
+const eventHistory = 256
+
+// presenceHub fans presence events out to the WatchPresence streams
+type presenceHub struct {
+	sync.Mutex
+	subscribers map[chan *pb.PresenceEvent]bool
+	history     []*pb.PresenceEvent
+	lastId      uint64
+}
+
+func newPresenceHub() *presenceHub {
+	return &presenceHub{subscribers: make(map[chan *pb.PresenceEvent]bool)}
+}
+
+// subscribe returns the buffered events after lastEventId followed by the events
+// published until ctx is done, the channel is closed after
+func (h *presenceHub) subscribe(ctx context.Context, lastEventId uint64) <-chan *pb.PresenceEvent {
+	h.Lock()
+	replay := make([]*pb.PresenceEvent, 0)
+	if lastEventId > 0 {
+		for _, event := range h.history {
+			if event.GetId() > lastEventId {
+				replay = append(replay, event)
+			}
+		}
+	}
+	events := make(chan *pb.PresenceEvent, 64+len(replay))
+	for _, event := range replay {
+		events <- event
+	}
+	h.subscribers[events] = true
+	h.Unlock()
+	go func() {
+		<-ctx.Done()
+		h.Lock()
+		delete(h.subscribers, events)
+		h.Unlock()
+		close(events)
+	}()
+	return events
+}
+
+// publish numbers event and sends it to every subscriber, a subscriber too slow to keep up misses it
+func (h *presenceHub) publish(event *pb.PresenceEvent) bool {
+	h.Lock()
+	defer h.Unlock()
+	h.lastId++
+	event.Id = h.lastId
+	h.history = append(h.history, event)
+	if len(h.history) > eventHistory {
+		h.history = h.history[len(h.history)-eventHistory:]
+	}
+	delivered := true
+	for events := range h.subscribers {
+		select {
+		case events <- event:
+		default:
+			delivered = false
+		}
+	}
+	return delivered
+}
+
+// deviceType returns the type metadata of the device in event
+func deviceType(event *pb.PresenceEvent) string {
+	if event.GetBle() != nil {
+		return "ble"
+	}
+	return metadataValue(event.GetDevice().GetMetadata(), "type")
+}
+
+// matches reports whether event passes the filters of request
+func matches(request *pb.WatchPresenceRequest, event *pb.PresenceEvent) bool {
+	if request.GetHome() != "" && request.GetHome() != event.GetHome() {
+		return false
+	}
+	if request.GetType() != "" && (event.GetDevice() != nil || event.GetBle() != nil) && request.GetType() != deviceType(event) {
+		return false
+	}
+	return true
+}
+
+// publishEvent stamps and sends a copy of event to the WatchPresence streams so
+// callers can keep changing the devices in it
+func (s *Server) publishEvent(event *pb.PresenceEvent) {
+	event = proto.Clone(event).(*pb.PresenceEvent)
+	event.Timestamp = time.Now().Unix()
+	if !s.events.publish(event) {
+		s.Logger.Warn("dropped presence event for a slow watcher", "type", event.GetType().String())
+	}
+}
+
+// WatchPresence streams the presence events matching request until the client goes away
+func (s *Server) WatchPresence(request *pb.WatchPresenceRequest, stream pb.HomeDetector_WatchPresenceServer) error {
+	for event := range s.events.subscribe(stream.Context(), request.GetLastEventId()) {
+		if !matches(request, event) {
+			continue
+		}
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
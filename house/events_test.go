@@ -0,0 +1,146 @@
+package house
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"github.com/gorilla/websocket"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchPresence(t *testing.T) {
+	h := newTestHarness(t)
+	lis := bufconn.Listen(1 << 20)
+	srv := gogrpc.NewServer()
+	pb.RegisterHomeDetectorServer(srv, h.Server)
+	go srv.Serve(lis)
+	defer srv.Stop()
+	conn, err := gogrpc.Dial("bufnet", gogrpc.WithInsecure(), gogrpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := pb.NewHomeDetectorClient(conn).WatchPresence(ctx, &pb.WatchPresenceRequest{Home: "aus", Type: "network"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		h.events.Lock()
+		defer h.events.Unlock()
+		return len(h.events.subscribers) == 1
+	}, "expected the stream to subscribe")
+
+	if err := h.Store.PutHome(context.Background(), "aus", false); err != nil {
+		t.Fatal(err)
+	}
+	phone := &pb.Devices{
+		Id:       &pb.NetworkId{Ip: "192.168.1.17", Mac: "AA:BB:CC:DD:EE:11", UUID: "AA:BB:CC:DD:EE:11"},
+		Home:     "aus",
+		Name:     "phone",
+		Person:   true,
+		Metadata: []*pb.Metadata{{Key: "type", Value: "network"}},
+	}
+	if err := h.WriteNetworkDevice(context.Background(), phone); err != nil {
+		t.Fatal(err)
+	}
+	// filtered out by home
+	h.report(t, "nz", &pb.AddressRequest{Ip: "10.0.0.2", Mac: "AA:BB:CC:DD:EE:12", Vendor: "Apple"})
+	h.report(t, "aus", &pb.AddressRequest{Ip: "192.168.1.18", Mac: "AA:BB:CC:DD:EE:13", Vendor: "Apple"})
+	h.report(t, "aus", &pb.AddressRequest{Ip: "192.168.1.17", Mac: "AA:BB:CC:DD:EE:11"})
+	deadline := time.AfterFunc(5*time.Second, cancel)
+	defer deadline.Stop()
+	expect := func(types ...pb.PresenceEventType) {
+		t.Helper()
+		want := make(map[pb.PresenceEventType]bool)
+		for _, eventType := range types {
+			want[eventType] = true
+		}
+		for len(want) > 0 {
+			event, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("still waiting for %v: %v", want, err)
+			}
+			if event.GetHome() != "aus" {
+				t.Errorf("expected only aus events, got %v", event)
+			}
+			if event.GetType() == pb.PresenceEventType_NEW_DEVICE_DISCOVERED && event.GetDevice().GetId().GetMac() != "AA:BB:CC:DD:EE:13" {
+				t.Errorf("unexpected new device %v", event.GetDevice())
+			}
+			delete(want, event.GetType())
+		}
+	}
+	expect(pb.PresenceEventType_NEW_DEVICE_DISCOVERED, pb.PresenceEventType_DEVICE_ARRIVED, pb.PresenceEventType_PERSON_ARRIVED)
+	h.mem.Advance(time.Duration(*TimeAwaySeconds+1) * time.Second)
+	expect(pb.PresenceEventType_DEVICE_LEFT, pb.PresenceEventType_PERSON_LEFT, pb.PresenceEventType_HOUSE_EMPTY_CHANGED)
+}
+
+func TestEventsEndpointResumes(t *testing.T) {
+	h := newTestHarness(t)
+	srv := httptest.NewServer(http.HandlerFunc(h.Events))
+	defer srv.Close()
+	h.publishEvent(&pb.PresenceEvent{Type: pb.PresenceEventType_HOUSE_EMPTY_CHANGED, Home: "aus", Empty: true})
+	h.publishEvent(&pb.PresenceEvent{Type: pb.PresenceEventType_HOUSE_EMPTY_CHANGED, Home: "nz", Empty: true})
+	h.publishEvent(&pb.PresenceEvent{Type: pb.PresenceEventType_HOUSE_EMPTY_CHANGED, Home: "aus", Empty: false})
+	first := h.events.history[0].GetId()
+
+	req, err := http.NewRequest("GET", srv.URL+"?home=aus", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(first, 10))
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type %s", res.Header.Get("Content-Type"))
+	}
+	reader := bufio.NewReader(res.Body)
+	lines := make([]string, 3)
+	for i := range lines {
+		if lines[i], err = reader.ReadString('\n'); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lines[0] != fmt.Sprintf("id: %d\n", first+2) || lines[1] != "event: HOUSE_EMPTY_CHANGED\n" {
+		t.Errorf("expected the second aus event, got %q", lines)
+	}
+	event := &pb.PresenceEvent{}
+	if err := protojson.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), event); err != nil {
+		t.Fatal(err)
+	}
+	if event.GetHome() != "aus" || event.GetEmpty() {
+		t.Errorf("unexpected event %v", event)
+	}
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"?home=nz&lastEventId="+strconv.FormatUint(first, 10), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	_, data, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := protojson.Unmarshal(data, event); err != nil {
+		t.Fatal(err)
+	}
+	if event.GetHome() != "nz" || event.GetId() != first+1 {
+		t.Errorf("expected the nz event to be replayed, got %v", event)
+	}
+}
@@ -0,0 +1,62 @@
+package house
+
+import (
+	"context"
+	"errors"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// gatewayHeader forwards the apikey header as gRPC metadata. The client and home headers are
+// dropped, the in-memory server has no client certificate to check them against
+func gatewayHeader(key string) (string, bool) {
+	switch key = strings.ToLower(key); key {
+	case "apikey":
+		return key, true
+	case "client", "home":
+		return "", false
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// Gateway returns an HTTP handler transcoding the JSON requests under /rpc/ to srv. The calls
+// go through an in-memory gRPC server with the unary and stream interceptors so they are
+// authorized and measured like those on the gRPC port, it stops once ctx is done. Every
+// request needs an admin key as the plain HTTP port bypasses client certificates
+func Gateway(ctx context.Context, srv pb.HomeDetectorServer, keys KeyStore, unary []gogrpc.UnaryServerInterceptor, stream []gogrpc.StreamServerInterceptor) (http.Handler, error) {
+	if keys == nil {
+		return nil, errors.New("the gateway needs -apikeys")
+	}
+	lis := bufconn.Listen(1 << 20)
+	internal := gogrpc.NewServer(gogrpc.ChainUnaryInterceptor(unary...), gogrpc.ChainStreamInterceptor(stream...))
+	pb.RegisterHomeDetectorServer(internal, srv)
+	go func() {
+		if err := internal.Serve(lis); err != nil {
+			log.Printf("gateway stopped: %v", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		internal.Stop()
+	}()
+	conn, err := gogrpc.DialContext(ctx, "bufconn",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(gatewayHeader))
+	err = pb.RegisterHomeDetectorHandler(ctx, mux, conn)
+	if err != nil {
+		return nil, err
+	}
+	return RequireAdminKey(keys, mux), nil
+}
@@ -0,0 +1,85 @@
+package house
+
+import (
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGatewayTranscodes(t *testing.T) {
+	h := newTestHarness(t)
+	keys, err := NewKeyStore([]*APIKey{
+		{Name: "ops", Key: "admin-key", Scopes: []string{ScopeAdmin}},
+		{Name: "agent", Key: "agent-key", Scopes: []string{ScopeAgentReport}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := Gateway(ctx, h.Server, nil, nil, nil); err == nil {
+		t.Error("expected the gateway to need api keys")
+	}
+	gateway, err := Gateway(ctx, h.Server, keys,
+		[]gogrpc.UnaryServerInterceptor{UnaryAuthInterceptor(keys)},
+		[]gogrpc.StreamServerInterceptor{StreamAuthInterceptor(keys)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(gateway)
+	defer srv.Close()
+
+	call := func(method, key, body string) (int, []byte) {
+		req, err := http.NewRequest("POST", srv.URL+"/rpc/v1/"+method, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != "" {
+			req.Header.Set("apikey", key)
+		}
+		res, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		data, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode, data
+	}
+
+	device := `{"Id": {"Ip": "192.168.1.13", "Mac": "AA:BB:CC:DD:EE:0A", "UUID": "AA:BB:CC:DD:EE:0A"}, "Home": "aus", "Name": "tv"}`
+	if status, data := call("UpdateDevice", "", device); status != http.StatusUnauthorized {
+		t.Errorf("expected the call without a key to be rejected, got %d %s", status, data)
+	}
+	if status, data := call("Address", "agent-key", `{"ip": "192.168.1.14"}`); status != http.StatusForbidden {
+		t.Errorf("expected the gateway to need an admin key, got %d %s", status, data)
+	}
+	status, data := call("UpdateDevice", "admin-key", device)
+	if status != http.StatusOK {
+		t.Fatalf("expected UpdateDevice to succeed, got %d %s", status, data)
+	}
+	reply := &pb.Reply{}
+	if err := protojson.Unmarshal(data, reply); err != nil || !reply.GetAcknowledged() {
+		t.Errorf("unexpected reply %s", data)
+	}
+	stored, err := h.Store.GetDevice(context.Background(), "AA:BB:CC:DD:EE:0A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.GetName() != "tv" {
+		t.Errorf("expected the device to be stored, got %v", stored)
+	}
+	status, data = call("ListDevices", "admin-key", "{}")
+	devices := &pb.DevicesResponse{}
+	if err := protojson.Unmarshal(data, devices); err != nil || status != http.StatusOK || len(devices.GetDevices()) != 1 {
+		t.Errorf("expected the device to be listed, got %d %s", status, data)
+	}
+}
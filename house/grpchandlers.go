@@ -2,13 +2,10 @@ package house
 
 import (
 	"context"
-	"fmt"
 	pb "github.com/beaujr/nmap_prometheus/proto"
 	"github.com/golang/protobuf/ptypes/empty"
-	etcdv3 "go.etcd.io/etcd/client/v3"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"log"
-	"path/filepath"
 	"sort"
 	"sync"
 	"time"
@@ -79,7 +76,8 @@ func (s *Server) TogglePerson(ctx context.Context, device *pb.Devices) (*pb.Repl
 	}
 	if !device.Away {
 		log.Printf("Toggling %s to alive value %s\n", device.GetId().GetMac(), path)
-		_, err = s.Kv.Put(ctx, filepath.Join(AlivePrefix, device.GetHome(), device.GetId().GetMac()), path)
+		// granted rather than put so the alive key still expires
+		err = s.Store.GrantLease(ctx, device.GetHome(), device.GetId().GetMac(), path, awayTimeout(device))
 		if err != nil {
 			return nil, err
 		}
@@ -128,23 +126,23 @@ func (s *Server) ListTimedCommands(ctx context.Context, _ *empty.Empty) (*pb.TCs
 	return &pb.TCsResponse{Bles: bles}, nil
 }
 
-// ListDevices lists all the Devices
-func (s *Server) ListDevices(ctx context.Context, _ *empty.Empty) (*pb.DevicesResponse, error) {
+// ListDevices lists the Devices matching the request a page at a time
+func (s *Server) ListDevices(ctx context.Context, request *pb.ListDevicesRequest) (*pb.DevicesResponse, error) {
 	//s.GrpcPrometheusMetrics(ctx, "grpc_address", "Address")
 	//s.GrpcHitsMetrics("grpc_address_count", "Address", 1)
-	devices, err := s.getDevices(ctx)
+	devices, err := s.listDevices(ctx, request)
 	if err != nil {
 		log.Printf("Error listing Devices: %v", err)
 		return nil, err
 	}
-	return &pb.DevicesResponse{Devices: devices}, nil
+	return devices, nil
 }
 
 // DeleteCommandQueue Deletes an entire job from CommandQueue
 func (s *Server) DeleteCommandQueue(ctx context.Context, request *pb.StringRequest) (*pb.Reply, error) {
 	//s.GrpcPrometheusMetrics(ctx, "grpc_address", "Address")
 	//s.GrpcHitsMetrics("grpc_address_count", "Address", 1)
-	_, err := s.Kv.Delete(ctx, fmt.Sprintf("%s%s", tcPrefix, request.Key))
+	err := s.Store.DeleteTimedCommand(ctx, request.Key)
 	if err != nil {
 		return nil, err
 	}
@@ -155,7 +153,7 @@ func (s *Server) DeleteCommandQueue(ctx context.Context, request *pb.StringReque
 func (s *Server) DeleteTimedCommand(ctx context.Context, request *pb.StringRequest) (*pb.Reply, error) {
 	//s.GrpcPrometheusMetrics(ctx, "grpc_address", "Address")
 	//s.GrpcHitsMetrics("grpc_address_count", "Address", 1)
-	_, err := s.Kv.Delete(ctx, fmt.Sprintf("%s%s", tcPrefix, request.Key), etcdv3.WithPrefix())
+	err := s.Store.DeleteTimedCommands(ctx, request.Key)
 	if err != nil {
 		return nil, err
 	}
@@ -223,13 +221,28 @@ func (s *Server) DeleteDevice(ctx context.Context, request *pb.StringRequest) (*
 	if request.Key == "" {
 		return &pb.Reply{Acknowledged: true}, nil
 	}
-	err := s.deleteDeviceById(request.Key)
+	device, err := s.Store.GetDevice(ctx, request.Key)
 	if err != nil {
 		return &pb.Reply{Acknowledged: false}, err
 	}
+	err = s.deleteDeviceById(request.Key)
+	if err != nil {
+		return &pb.Reply{Acknowledged: false}, err
+	}
+	if device != nil {
+		err = s.forgetDevice(ctx, device)
+		if err != nil {
+			return &pb.Reply{Acknowledged: false}, err
+		}
+	}
 	return &pb.Reply{Acknowledged: true}, nil
 }
 
+// MergeDevices Handler for folding duplicate device records into one
+func (s *Server) MergeDevices(ctx context.Context, request *pb.MergeDevicesRequest) (*pb.Devices, error) {
+	return s.mergeDeviceIds(ctx, request)
+}
+
 // UpdateDevice Handler for updating Devices
 func (s *Server) UpdateDevice(ctx context.Context, request *pb.Devices) (*pb.Reply, error) {
 	//s.GrpcPrometheusMetrics(ctx, "grpc_address", "Address")
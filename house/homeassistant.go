@@ -0,0 +1,271 @@
+package house
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/beaujr/nmap_prometheus/broker"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"log/slog"
+	"regexp"
+	"sync"
+	"time"
+)
+
+var (
+	homeAssistantEnabled = flag.Bool("homeassistant", false, "Publish Home Assistant MQTT discovery for people, requires -mqtt")
+	discoveryPrefix      = flag.String("haDiscoveryPrefix", "homeassistant", "Home Assistant MQTT discovery prefix")
+)
+
+const (
+	haHome    = "home"
+	haNotHome = "not_home"
+)
+
+// objectIdInvalid matches the characters Home Assistant doesn't allow in an object id
+var objectIdInvalid = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Publisher publishes retained MQTT messages
+type Publisher interface {
+	PublishRetained(topic string, payload []byte) error
+}
+
+// MQTTPublisher is an implementation of the Publisher
+type MQTTPublisher struct {
+	client mqtt.Client
+}
+
+// PublishRetained publishes payload to topic at QoS 1, an empty payload clears the topic
+func (m *MQTTPublisher) PublishRetained(topic string, payload []byte) error {
+	token := m.client.Publish(topic, 1, true, payload)
+	if !token.WaitTimeout(10 * time.Second) {
+		return fmt.Errorf("timed out publishing to %s", topic)
+	}
+	return token.Error()
+}
+
+// homeAssistant queues device_tracker discovery and state for run to publish, remembering what
+// was last published so unchanged payloads aren't sent on every report
+type homeAssistant struct {
+	sync.Mutex
+	published map[string]string
+	// pending holds the latest payload of the topics in queue, in the order they were first queued
+	pending map[string]string
+	queue   []string
+	wake    chan struct{}
+}
+
+// newHomeAssistant returns the queue when -homeassistant is set and nil otherwise
+func newHomeAssistant() *homeAssistant {
+	if !*homeAssistantEnabled {
+		return nil
+	}
+	return &homeAssistant{published: make(map[string]string), pending: make(map[string]string), wake: make(chan struct{}, 1)}
+}
+
+type haDevice struct {
+	Identifiers  []string   `json:"identifiers"`
+	Name         string     `json:"name"`
+	Manufacturer string     `json:"manufacturer,omitempty"`
+	Connections  [][]string `json:"connections,omitempty"`
+}
+
+type haDiscovery struct {
+	Name           string   `json:"name"`
+	UniqueId       string   `json:"unique_id"`
+	ObjectId       string   `json:"object_id"`
+	StateTopic     string   `json:"state_topic"`
+	PayloadHome    string   `json:"payload_home"`
+	PayloadNotHome string   `json:"payload_not_home"`
+	SourceType     string   `json:"source_type"`
+	Device         haDevice `json:"device"`
+}
+
+// publish queues payload for topic, replacing a payload still waiting for it
+func (ha *homeAssistant) publish(topic string, payload []byte) {
+	ha.Lock()
+	defer ha.Unlock()
+	if _, ok := ha.pending[topic]; !ok {
+		if last, ok := ha.published[topic]; ok && last == string(payload) {
+			return
+		}
+		ha.queue = append(ha.queue, topic)
+	}
+	ha.pending[topic] = string(payload)
+	select {
+	case ha.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next takes the oldest queued topic whose payload differs from what was last published
+func (ha *homeAssistant) next() (string, string, bool) {
+	ha.Lock()
+	defer ha.Unlock()
+	for len(ha.queue) > 0 {
+		topic := ha.queue[0]
+		ha.queue = ha.queue[1:]
+		payload := ha.pending[topic]
+		delete(ha.pending, topic)
+		if last, ok := ha.published[topic]; !ok || last != payload {
+			return topic, payload, true
+		}
+	}
+	return "", "", false
+}
+
+// run publishes the queue through publisher until ctx is done, the lock isn't held while publishing
+// so reports never wait on the broker. A payload that fails is logged and sent again on the next change
+func (ha *homeAssistant) run(ctx context.Context, publisher Publisher, logger *slog.Logger) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ha.wake:
+		}
+		for topic, payload, ok := ha.next(); ok; topic, payload, ok = ha.next() {
+			err := publisher.PublishRetained(topic, []byte(payload))
+			if err != nil {
+				logger.Error(err.Error())
+				continue
+			}
+			ha.Lock()
+			ha.published[topic] = payload
+			ha.Unlock()
+		}
+	}
+}
+
+func haObjectId(prefix, id string) string {
+	return prefix + "_" + objectIdInvalid.ReplaceAllString(id, "_")
+}
+
+func haStateTopic(objectId string) string {
+	return broker.Path("tracker", objectId, "state")
+}
+
+func haConfigTopic(objectId string) string {
+	return fmt.Sprintf("%s/device_tracker/%s/config", *discoveryPrefix, objectId)
+}
+
+// track queues the discovery config and home or not_home state of a tracker
+func (ha *homeAssistant) track(config *haDiscovery, home bool) error {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	ha.publish(haConfigTopic(config.ObjectId), payload)
+	state := haNotHome
+	if home {
+		state = haHome
+	}
+	ha.publish(config.StateTopic, []byte(state))
+	return nil
+}
+
+// untrack queues clearing the retained discovery config so Home Assistant removes the tracker
+func (ha *homeAssistant) untrack(objectId string) error {
+	config, state := haConfigTopic(objectId), haStateTopic(objectId)
+	ha.Lock()
+	last, published := ha.published[config]
+	next, pending := ha.pending[config]
+	// the state is published again with the config when the tracker comes back
+	delete(ha.published, state)
+	ha.Unlock()
+	if (published && last != "") || (pending && next != "") {
+		ha.publish(config, []byte{})
+	}
+	return nil
+}
+
+// EnableHomeAssistant starts publishing the queued discovery through client and publishes every person
+// and person device, the queue is created by NewServer so reports before this are kept
+func (s *Server) EnableHomeAssistant(client mqtt.Client) {
+	if s.homeAssistant == nil {
+		return
+	}
+	go s.homeAssistant.run(s.GetContext(), &MQTTPublisher{client: client}, s.Logger)
+	devices, err := s.ReadNetworkConfig()
+	if err != nil {
+		s.Logger.Error(err.Error())
+	}
+	for _, device := range devices {
+		s.publishDeviceTracker(device)
+	}
+	err = s.registerPeopleMetrics(s.GetContext())
+	if err != nil {
+		s.Logger.Error(err.Error())
+	}
+}
+
+// publishDeviceTracker publishes a device_tracker for devices flagged Person and removes it otherwise
+func (s *Server) publishDeviceTracker(device *pb.Devices) {
+	if s.homeAssistant == nil {
+		return
+	}
+	objectId := haObjectId("device", device.GetId().GetUUID())
+	var err error
+	if device.GetPerson() {
+		err = s.homeAssistant.track(&haDiscovery{
+			Name:           device.GetName(),
+			UniqueId:       fmt.Sprintf("nmap_prometheus_%s", objectId),
+			ObjectId:       objectId,
+			StateTopic:     haStateTopic(objectId),
+			PayloadHome:    haHome,
+			PayloadNotHome: haNotHome,
+			SourceType:     "router",
+			Device: haDevice{
+				Identifiers:  []string{device.GetId().GetUUID()},
+				Name:         device.GetName(),
+				Manufacturer: device.GetManufacturer(),
+				Connections:  [][]string{{"mac", device.GetId().GetMac()}},
+			},
+		}, !device.GetAway())
+	} else {
+		err = s.homeAssistant.untrack(objectId)
+	}
+	if err != nil {
+		s.Logger.Error(err.Error())
+	}
+}
+
+// publishPersonTrackers publishes a device_tracker for every stored person in humans
+func (s *Server) publishPersonTrackers(ctx context.Context, humans map[string]*pb.People) {
+	if s.homeAssistant == nil {
+		return
+	}
+	stored, err := s.Store.ListPeople(ctx)
+	if err != nil {
+		s.Logger.Error(err.Error())
+		return
+	}
+	for name := range stored {
+		human := humans[name]
+		objectId := haObjectId("person", name)
+		err = s.homeAssistant.track(&haDiscovery{
+			Name:           name,
+			UniqueId:       fmt.Sprintf("nmap_prometheus_%s", objectId),
+			ObjectId:       objectId,
+			StateTopic:     haStateTopic(objectId),
+			PayloadHome:    haHome,
+			PayloadNotHome: haNotHome,
+			SourceType:     "router",
+			Device:         haDevice{Identifiers: []string{objectId}, Name: name},
+		}, human != nil && !human.GetAway())
+		if err != nil {
+			s.Logger.Error(err.Error())
+		}
+	}
+}
+
+// removePersonTracker removes the device_tracker of a deleted person
+func (s *Server) removePersonTracker(name string) {
+	if s.homeAssistant == nil {
+		return
+	}
+	if err := s.homeAssistant.untrack(haObjectId("person", name)); err != nil {
+		s.Logger.Error(err.Error())
+	}
+}
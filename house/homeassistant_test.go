@@ -0,0 +1,42 @@
+package house
+
+import (
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHomeAssistantDeviceTracker(t *testing.T) {
+	h := newTestHarness(t)
+	publisher := &recordingPublisher{retained: make(map[string]string)}
+	h.homeAssistant = &homeAssistant{published: make(map[string]string), pending: make(map[string]string), wake: make(chan struct{}, 1)}
+	go h.homeAssistant.run(h.GetContext(), publisher, h.Logger)
+	phone := &pb.Devices{
+		Id:     &pb.NetworkId{Ip: "192.168.1.16", Mac: "AA:BB:CC:DD:EE:10", UUID: "AA:BB:CC:DD:EE:10"},
+		Home:   "aus",
+		Name:   "phone",
+		Person: true,
+	}
+	if err := h.WriteNetworkDevice(context.Background(), phone); err != nil {
+		t.Fatal(err)
+	}
+	state := "nmap_prometheus/tracker/device_AA_BB_CC_DD_EE_10/state"
+	eventually(t, func() bool { return publisher.get(state) == "home" }, "expected phone to be home")
+	config := publisher.get("homeassistant/device_tracker/device_AA_BB_CC_DD_EE_10/config")
+	if !strings.Contains(config, `"state_topic":"nmap_prometheus/tracker/device_AA_BB_CC_DD_EE_10/state"`) {
+		t.Errorf("unexpected discovery config: %s", config)
+	}
+	h.report(t, "aus", &pb.AddressRequest{Ip: "192.168.1.16", Mac: "AA:BB:CC:DD:EE:10"})
+	h.mem.Advance(time.Duration(*TimeAwaySeconds+1) * time.Second)
+	eventually(t, func() bool { return publisher.get(state) == "not_home" }, "expected phone to be not_home once its lease expired")
+
+	phone.Person = false
+	if err := h.WriteNetworkDevice(context.Background(), phone); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		return publisher.get("homeassistant/device_tracker/device_AA_BB_CC_DD_EE_10/config") == ""
+	}, "expected the tracker to be removed")
+}
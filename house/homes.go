@@ -3,9 +3,7 @@ package house
 import (
 	"flag"
 	"fmt"
-	"gopkg.in/yaml.v2"
-	"log"
-	"strconv"
+	pb "github.com/beaujr/nmap_prometheus/proto"
 	"strings"
 )
 
@@ -13,24 +11,8 @@ var (
 	houseTimeOut = flag.Int64("absence", 3600, "How long a house is empty (in seconds) before turning off smart devices.")
 )
 
-type home struct {
-	Empty   bool `json:"empty",yaml:"empty"`
-	Timeout int  `json:"timeout",yaml:"timeout"`
-}
-
-func (s *Server) writeHome(id string, item *home) error {
-	d1, err := yaml.Marshal(item)
-	if err != nil {
-		log.Fatalf(err.Error())
-	}
-
-	key := fmt.Sprintf("%s%s", HomePrefix, id)
-	_, err = s.Kv.Put(s.GetContext(), key, string(d1))
-	return err
-}
-
 func (s *Server) ToggleHouseStatus(home string, houseEmpty bool) error {
-	_, err := s.Kv.Put(s.GetContext(), fmt.Sprintf("%s%s", HomePrefix, home), strconv.FormatBool(houseEmpty))
+	err := s.Store.PutHome(s.GetContext(), home, houseEmpty)
 	if err != nil {
 		s.Logger.Error(err.Error())
 		return err
@@ -55,18 +37,19 @@ func (s *Server) ToggleHouseStatus(home string, houseEmpty bool) error {
 		}
 	}
 	if !houseEmpty {
-		keys, err := s.getTcKeys()
+		tcs, err := s.getTc()
 		if err != nil {
 			return err
 		}
-		for _, key := range keys {
-			if strings.Contains(*key, home) {
-				err = s.deleteTcByKey(*key)
+		for key, val := range tcs {
+			if strings.Contains(key, home) {
+				err = s.deleteTc(val)
 				if err != nil {
 					return err
 				}
 			}
 		}
 	}
+	s.publishEvent(&pb.PresenceEvent{Type: pb.PresenceEventType_HOUSE_EMPTY_CHANGED, Home: home, Empty: houseEmpty})
 	return s.NotificationClient.SendNotification("House Empty", body, home)
 }
@@ -0,0 +1,53 @@
+package house
+
+import (
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"testing"
+)
+
+func TestToggleHouseStatus(t *testing.T) {
+	h := newTestHarness(t)
+	tv := &pb.Devices{
+		Id:            &pb.NetworkId{Ip: "192.168.1.8", Mac: "AA:BB:CC:DD:EE:04", UUID: "AA:BB:CC:DD:EE:04"},
+		Home:          "aus",
+		Name:          "TV",
+		PresenceAware: true,
+	}
+	if err := h.WriteNetworkDevice(context.Background(), tv); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.ToggleHouseStatus("aus", true); err != nil {
+		t.Fatal(err)
+	}
+	homes, err := h.ReadHomesConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty := homes["aus"]; empty == nil || !*empty {
+		t.Errorf("expected aus to be empty, got %v", homes)
+	}
+	tcs, err := h.getTc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tcs) != 1 {
+		t.Fatalf("expected one timed command, got %d", len(tcs))
+	}
+	for _, tc := range tcs {
+		if tc.GetCommand() != "Turn TV off" {
+			t.Errorf("unexpected command: %s", tc.GetCommand())
+		}
+	}
+
+	if err := h.ToggleHouseStatus("aus", false); err != nil {
+		t.Fatal(err)
+	}
+	tcs, err = h.getTc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tcs) != 0 {
+		t.Errorf("expected timed commands to be cancelled, got %v", tcs)
+	}
+}
@@ -6,24 +6,23 @@ import (
 	"flag"
 	"fmt"
 	"github.com/beaujr/nmap_prometheus/agent"
-	"github.com/beaujr/nmap_prometheus/etcd"
 	pb "github.com/beaujr/nmap_prometheus/proto"
-	"github.com/ghodss/yaml"
 	"github.com/robfig/cron/v3"
-	etcdv3 "go.etcd.io/etcd/client/v3"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	api "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
 	"io/ioutil"
 	"log"
 	"log/slog"
 	"net/http"
 	"os"
-	"path/filepath"
 	"slices"
-	"strconv"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -39,6 +38,7 @@ var (
 	debug              = flag.Bool("debug", false, "Debug mode")
 	cqEnabled          = flag.Bool("cq", false, "Command Queue Enabled")
 	newDeviceIsPerson  = flag.Bool("newDeviceIsPerson", false, "Track new devices as people")
+	osMetricLabel      = flag.Bool("osMetricLabel", false, "Label the device metrics with the operating system nmap detected")
 )
 
 var bleDevices = []*pb.BleDevices{}
@@ -54,11 +54,13 @@ var (
 	tcPrefix            = "/cq/"
 	peoplePrefix        = "/people/"
 	notificationsPrefix = "/notifications/"
+	vendorsPrefix       = "/vendors/"
+	indexPrefix         = "/index/"
 )
 
 const meterName = "github.com/beaujr/nmap_prometheus"
 
-var devices, lastseen, distance, bledistance, cq api.Float64ObservableGauge
+var devices, lastseen, distance, bledistance, cq, personHome, openPort api.Float64ObservableGauge
 var grpc, grpcEndpoint api.Int64Counter
 var grpcAgentEndpoint api.Int64ObservableGauge
 var meter api.Meter
@@ -108,6 +110,14 @@ func init() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	personHome, err = meter.Float64ObservableGauge("home_detector_person_home", api.WithDescription("Person in home"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	openPort, err = meter.Float64ObservableGauge("home_detector_device_open_port", api.WithDescription("Port found open by the last service scan"))
+	if err != nil {
+		log.Fatal(err)
+	}
 }
 
 //
@@ -129,118 +139,30 @@ type HomeManager interface {
 	Devices(w http.ResponseWriter, req *http.Request)
 	People(w http.ResponseWriter, req *http.Request)
 	HomeEmptyState(w http.ResponseWriter, req *http.Request)
+	Events(w http.ResponseWriter, req *http.Request)
+	API(w http.ResponseWriter, req *http.Request)
 	GetContext() context.Context
 }
 
 // Server is an implementation of the proto HomeDetectorServer
 type Server struct {
 	pb.UnimplementedHomeDetectorServer
-	Kv                 etcdv3.KV
+	Store              Store
 	AssistantClient    GoogleAssistant
-	EtcdClient         Leaser
 	NotificationClient Notifier
 	ctx                context.Context
 	Logger             *slog.Logger
 	gauges             *observable
+	homeAssistant      *homeAssistant
+	events             *presenceHub
+	vendors            *vendorQueue
+	classifier         *Classifier
 }
 
 func (s *Server) deviceManager(ctx context.Context) error {
 	return nil
 }
 
-type Leaser interface {
-	GrantLease(ctx context.Context, path, mac string, ttl int64) (string, *etcdv3.LeaseID, error)
-	DeleteLeaseByKey(ctx context.Context, key string) error
-	GetLeaseByKey(ctx context.Context, key string) (*etcdv3.LeaseStatus, *etcdv3.LeaseTimeToLiveResponse, error)
-}
-
-type EtcdLeaser struct {
-	etcdv3.Lease
-}
-
-func (leaser *EtcdLeaser) GetLeaseByKey(ctx context.Context, key string) (*etcdv3.LeaseStatus, *etcdv3.LeaseTimeToLiveResponse, error) {
-	leases, err := leaser.Leases(ctx)
-	if err != nil {
-		return nil, nil, err
-	}
-	for _, lease := range leases.Leases {
-		leaseTTL, err := leaser.TimeToLive(ctx, lease.ID, etcdv3.WithAttachedKeys())
-		if err != nil {
-			return nil, nil, err
-		}
-		for _, leasedItem := range leaseTTL.Keys {
-			if strings.Compare(string(leasedItem), key) == 0 {
-				return &lease, leaseTTL, nil
-			}
-		}
-	}
-	return nil, nil, nil
-}
-
-func (leaser *EtcdLeaser) DeleteLeaseByKey(ctx context.Context, key string) error {
-	leases, err := leaser.Leases(ctx)
-	if err != nil {
-		return err
-	}
-	for _, lease := range leases.Leases {
-		leaseTTL, err := leaser.TimeToLive(ctx, lease.ID, etcdv3.WithAttachedKeys())
-		if err != nil {
-			return err
-		}
-		for _, leasedItem := range leaseTTL.Keys {
-			if strings.Compare(string(leasedItem), key) == 0 {
-				_, err = leaser.Revoke(ctx, lease.ID)
-				if err != nil {
-					return err
-				}
-			}
-		}
-	}
-	return nil
-}
-
-func NewEtcdLeaser(lease etcdv3.Lease) Leaser {
-	return &EtcdLeaser{lease}
-}
-
-func (leaser *EtcdLeaser) GrantLease(ctx context.Context, path, mac string, ttl int64) (string, *etcdv3.LeaseID, error) {
-	keyPath := filepath.Join(AlivePrefix, path, mac)
-	leaseExists := false
-	leases, err := leaser.Leases(ctx)
-	if err != nil {
-		return "", nil, err
-	}
-	for _, lease := range leases.Leases {
-		leaseTTL, err := leaser.TimeToLive(ctx, lease.ID, etcdv3.WithAttachedKeys())
-		if err != nil {
-			return "", nil, err
-		}
-		for _, key := range leaseTTL.Keys {
-			if strings.Compare(keyPath, string(key)) == 0 {
-				leaseExists = true
-				_, err := leaser.KeepAliveOnce(ctx, leaseTTL.ID)
-				if err != nil {
-					return "", nil, err
-				}
-
-			}
-		}
-		if leaseExists {
-			break
-		}
-	}
-	if !leaseExists {
-		lease, err := leaser.Grant(ctx, ttl)
-		if err != nil {
-			return "", nil, err
-		}
-		if lease != nil {
-			return keyPath, &lease.ID, err
-		}
-	}
-	return "", nil, nil
-}
-
 func writeConfig(data []byte, filename string) error {
 	err := ioutil.WriteFile(filename, data, 0644)
 	if err != nil {
@@ -267,10 +189,14 @@ func (s ByExecutedAt) Less(i, j int) bool {
 }
 
 // NewCustomServer function to allow passing in Server dependencies
-func NewCustomServer(ctx context.Context, e etcdv3.KV, g GoogleAssistant, n Notifier, handler slog.Handler) Server {
-	s := Server{
+func NewCustomServer(ctx context.Context, st Store, g GoogleAssistant, n Notifier, handler slog.Handler) *Server {
+	classifier, err := ParseClassifier(defaultCategoryRules)
+	if err != nil {
+		log.Panicln(err.Error())
+	}
+	s := &Server{
 		UnimplementedHomeDetectorServer: pb.UnimplementedHomeDetectorServer{},
-		Kv:                              e,
+		Store:                           st,
 		AssistantClient:                 g,
 		NotificationClient:              n,
 		ctx:                             ctx,
@@ -279,8 +205,13 @@ func NewCustomServer(ctx context.Context, e etcdv3.KV, g GoogleAssistant, n Noti
 			Mutex: sync.Mutex{},
 			items: make(map[string]interface{}),
 		},
+		events:     newPresenceHub(),
+		vendors:    newVendorQueue(),
+		classifier: classifier,
 	}
-	createCrons(&s)
+	createCrons(s)
+	watchLeases(s)
+	resolveVendors(s)
 	s.loadMetrics()
 	return s
 }
@@ -305,17 +236,30 @@ func createCrons(server *Server) {
 			}
 		})
 	}
+	c.AddFunc(fmt.Sprintf("@every %ds", *vendorRefresh), func() {
+		err := server.refreshVendors(server.GetContext())
+		if err != nil {
+			server.Logger.Error(err.Error())
+		}
+	})
 	c.Start()
 	return
 }
 
 // NewServer new instance of HomeManager
 func NewServer(ctx context.Context) HomeManager {
-	client, etcdClient := etcd.NewClient(strings.Split(*etcdServers, ","))
+	store, err := NewStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	classifier, err := ReadClassifier()
+	if err != nil {
+		log.Fatal(err)
+	}
 	assistantClient := NewAssistant()
-	notifyClient := NewNotifier(etcdClient)
-	server := &Server{Kv: etcdClient, AssistantClient: assistantClient, NotificationClient: notifyClient, EtcdClient: NewEtcdLeaser(client.Lease), ctx: ctx, Logger: slog.New(slog.NewTextHandler(os.Stderr, nil))}
-	_, err := server.ReadNetworkConfig()
+	notifyClient := NewNotifier(store)
+	server := &Server{Store: store, AssistantClient: assistantClient, NotificationClient: notifyClient, ctx: ctx, Logger: slog.New(slog.NewTextHandler(os.Stderr, nil)), gauges: &observable{items: make(map[string]interface{})}, events: newPresenceHub(), vendors: newVendorQueue(), classifier: classifier, homeAssistant: newHomeAssistant()}
+	_, err = server.ReadNetworkConfig()
 	if err != nil {
 		server.Logger.Error(err.Error())
 	}
@@ -344,13 +288,14 @@ func NewServer(ctx context.Context) HomeManager {
 	}
 
 	for _, home := range homes {
-		homeKey := fmt.Sprintf("%s%s", HomePrefix, home)
-		_, err := server.Kv.Put(ctx, homeKey, strconv.FormatBool(server.IsHouseEmpty(ctx, home)))
+		err := server.Store.PutHome(ctx, home, server.IsHouseEmpty(ctx, home))
 		if err != nil {
 			server.Logger.Error(err.Error())
 		}
 	}
 	createCrons(server)
+	watchLeases(server)
+	resolveVendors(server)
 	server.loadMetrics()
 	return server
 }
@@ -359,28 +304,83 @@ func (s *Server) GetContext() context.Context {
 	return s.ctx
 }
 
-// Devices API endpoint to determine devices status
+// Devices API endpoint to determine devices status, a POST of a device updates it
 func (s *Server) Devices(w http.ResponseWriter, req *http.Request) {
-	devices := make([]*pb.Devices, 0)
-	items, err := s.ReadNetworkConfig()
+	if req.Method == http.MethodPost {
+		s.updateDevice(w, req)
+		return
+	}
+	request, err := devicesQuery(req.URL.Query())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	for _, device := range items {
-		devices = append(devices, device)
+	devices, err := s.listDevices(req.Context(), request)
+	if status.Code(err) == codes.InvalidArgument {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	js, err := json.Marshal(devices)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
+	js, err := json.Marshal(devices.GetDevices())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if devices.GetNextPageToken() != "" {
+		w.Header().Set("Next-Page-Token", devices.GetNextPageToken())
+	}
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
 	w.Write(js)
 	return
 }
 
+// updateDevice merges the JSON device in the request body onto the known device, fields left out keep their value
+func (s *Server) updateDevice(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	device := &pb.Devices{}
+	err = json.Unmarshal(body, device)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if device.GetId().GetUUID() == "" {
+		http.Error(w, "device Id.UUID is required", http.StatusBadRequest)
+		return
+	}
+	exDevice, err := s.Store.GetDevice(req.Context(), device.GetId().GetUUID())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if exDevice == nil {
+		http.Error(w, fmt.Sprintf("unknown device: %s", device.GetId().GetUUID()), http.StatusNotFound)
+		return
+	}
+	err = json.Unmarshal(body, exDevice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if exDevice.GetAwayTimeout() < 0 {
+		http.Error(w, "AwayTimeout must not be negative", http.StatusBadRequest)
+		return
+	}
+	_, err = s.UpdateDevice(req.Context(), exDevice)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // People API endpoint to determine person device status
 func (s *Server) People(w http.ResponseWriter, req *http.Request) {
 	people := make([]*pb.Devices, 0)
@@ -426,6 +426,7 @@ func (s *Server) HomeEmptyState(w http.ResponseWriter, req *http.Request) {
 type networkDeviceGauge struct {
 	away, lastseen, latency float64
 	attrs                   api.MeasurementOption
+	ports                   []api.MeasurementOption
 }
 
 func (o *observable) observe(ctx context.Context, obs api.Observer) error {
@@ -437,10 +438,16 @@ func (o *observable) observe(ctx context.Context, obs api.Observer) error {
 			obs.ObserveFloat64(devices, item.away, item.attrs)
 			obs.ObserveFloat64(lastseen, item.lastseen, item.attrs)
 			obs.ObserveFloat64(distance, item.latency, item.attrs)
+			for _, port := range item.ports {
+				obs.ObserveFloat64(openPort, 1, port)
+			}
 		case *bluetoothDevice:
 			d := val.(*bluetoothDevice)
 			obs.ObserveFloat64(lastseen, d.lastseen, d.attrs, api.WithAttributes([]attribute.KeyValue{attribute.Key("person").Bool(false)}...))
 			obs.ObserveFloat64(bledistance, d.latency, d.attrs, api.WithAttributes([]attribute.KeyValue{attribute.Key("agent").String(d.agent)}...))
+		case *personGauge:
+			p := val.(*personGauge)
+			obs.ObserveFloat64(personHome, p.home, p.attrs)
 		case grpcClient:
 			d := val.(grpcClient)
 			obs.ObserveInt64(grpcAgentEndpoint, time.Now().Unix(), d.attrs)
@@ -456,9 +463,34 @@ type bluetoothDevice struct {
 	agent             string
 }
 
+// awayTimeout returns the seconds item can go unseen before it is away, the
+// device's AwayTimeout when set otherwise -timeout
+func awayTimeout(item *pb.Devices) int64 {
+	if item.GetAwayTimeout() > 0 {
+		return item.GetAwayTimeout()
+	}
+	return *TimeAwaySeconds
+}
+
+// bleAwayTimeout returns the BleDevices AwayTimeout when set otherwise -bleTimeout
+func bleAwayTimeout(item *pb.BleDevices) int64 {
+	if item.GetAwayTimeout() > 0 {
+		return item.GetAwayTimeout()
+	}
+	return *BleTimeAwaySeconds
+}
+
+// osLabel returns the family and generation of os eg Linux 4.X, blank when it is unknown
+func osLabel(os *pb.OSMatch) string {
+	if os.GetFamily() == "" {
+		return os.GetName()
+	}
+	return strings.TrimSpace(os.GetFamily() + " " + os.GetGeneration())
+}
+
 func (s *Server) RegisterMetric(item *pb.Devices) {
 	away := float64(1)
-	if (time.Now().Unix() - item.GetLastSeen()) > *TimeAwaySeconds {
+	if item.GetAway() || (time.Now().Unix()-item.GetLastSeen()) > awayTimeout(item) {
 		away = float64(0)
 	}
 
@@ -467,9 +499,21 @@ func (s *Server) RegisterMetric(item *pb.Devices) {
 		attribute.Key("mac").String(item.GetId().GetMac()),
 		attribute.Key("home").String(item.GetHome()),
 		attribute.Key("person").Bool(item.GetPerson()),
+		attribute.Key("category").String(item.GetCategory()),
+	}
+	if *osMetricLabel {
+		attrs = append(attrs, attribute.Key("os").String(osLabel(item.GetOS())))
+	}
+	ports := make([]api.MeasurementOption, 0, len(item.GetOpenPorts()))
+	for _, port := range item.GetOpenPorts() {
+		ports = append(ports, api.WithAttributes(append(slices.Clip(attrs),
+			attribute.Key("port").Int(int(port.GetPort())),
+			attribute.Key("protocol").String(port.GetProtocol()),
+			attribute.Key("service").String(port.GetService()),
+		)...))
 	}
 	s.gauges.Lock()
-	d := networkDeviceGauge{away: away, lastseen: float64(item.GetLastSeen()), latency: float64(item.GetLatency()), attrs: api.WithAttributes(attrs...)}
+	d := networkDeviceGauge{away: away, lastseen: float64(item.GetLastSeen()), latency: float64(item.GetLatency()), attrs: api.WithAttributes(attrs...), ports: ports}
 	s.gauges.items[item.GetId().GetMac()] = &d
 	s.gauges.Unlock()
 }
@@ -500,7 +544,7 @@ func (s *Server) loadMetrics() {
 	if err != nil {
 		s.Logger.Info(err.Error())
 	}
-	_, err = meter.RegisterCallback(s.gauges.observe, lastseen, distance, devices, bledistance, lastseen, grpcAgentEndpoint)
+	_, err = meter.RegisterCallback(s.gauges.observe, lastseen, distance, devices, bledistance, lastseen, grpcAgentEndpoint, personHome, openPort)
 	if err != nil {
 		log.Panicln(err.Error())
 	}
@@ -515,6 +559,10 @@ func (s *Server) loadMetrics() {
 	for _, item := range bles {
 		s.RegisterBleMetric(item, "etcd")
 	}
+	err = s.registerPeopleMetrics(s.GetContext())
+	if err != nil {
+		s.Logger.Error(err.Error())
+	}
 }
 
 func (s *Server) callAssistant(command string) (*string, error) {
@@ -543,7 +591,7 @@ func (s *Server) newBleDevice(in *pb.StringRequest) error {
 	return nil
 }
 
-func (s *Server) newDevice(ctx context.Context, in *pb.AddressRequest, home string, md []*pb.Metadata) error {
+func (s *Server) newDevice(ctx context.Context, in *pb.AddressRequest, home string, md []*pb.Metadata) (*pb.Devices, error) {
 	name := in.Ip
 
 	if in.Mac != "" && len(in.GetHosts()) == 0 {
@@ -558,15 +606,15 @@ func (s *Server) newDevice(ctx context.Context, in *pb.AddressRequest, home stri
 	if in.GetVendor() != "" {
 		vendor = in.GetVendor()
 		name = vendor
-	} else if in.Mac != in.Ip && strings.Contains(in.Mac, ":") {
-		macVendor, err := GetManufacturer(in.Mac)
+	} else if in.Mac != in.Ip && strings.Contains(in.Mac, ":") && !randomizedMac(in.Mac) {
+		macVendor, err := s.vendorOf(ctx, in.Mac)
 		if macVendor != nil {
 			vendor = *macVendor
 			name = vendor
 		}
 		if err != nil {
+			// left unknown so refreshVendors looks it up again
 			s.Logger.Error(err.Error())
-			vendor = name
 		}
 	}
 	newDevice := pb.Devices{
@@ -574,28 +622,58 @@ func (s *Server) newDevice(ctx context.Context, in *pb.AddressRequest, home stri
 		Id:           &pb.NetworkId{Ip: in.Ip, Mac: in.Mac, UUID: in.Mac},
 		Away:         false,
 		LastSeen:     int64(time.Now().Unix()),
+		Reported:     reportedAt(ctx),
 		Person:       *newDeviceIsPerson,
 		Command:      "",
 		Manufacturer: vendor,
 		Home:         home,
 		Hostnames:    in.Hosts,
 		Metadata:     md,
+		Randomized:   randomizedMac(in.Mac),
+		Services:     in.Services,
+		OS:           in.Os,
 	}
 	if len(in.Hosts) > 0 {
 		newDevice.Name = in.Hosts[0]
 	}
+	// a new device is already notified about so its ports are not
+	updateOpenPorts(&newDevice, in)
+	// the category rules know better than -newDeviceIsPerson
+	if rule := s.classify(&newDevice); rule != nil {
+		if rule.Person != nil {
+			newDevice.Person = *rule.Person
+		}
+		if rule.PresenceAware != nil {
+			newDevice.PresenceAware = *rule.PresenceAware
+		}
+	}
 	s.Logger.Info(fmt.Sprintf("New Device: %s", name))
 
 	err := s.WriteNetworkDevice(ctx, &newDevice)
 	if err != nil {
-		s.Logger.Info("Error saving to ETCD: %s", err.Error())
+		s.Logger.Error(fmt.Sprintf("Error saving to store: %s", err.Error()))
 	}
-	err = s.NotificationClient.SendNotification(fmt.Sprintf("New Device in %s (%s)", newDevice.Home, newDevice.Id.Ip), fmt.Sprintf("%s (%s)", newDevice.Name, newDevice.Manufacturer), newDevice.Home)
-	if err != nil {
-		s.Logger.Info("Error sending notification: %s", err.Error())
+	// a randomized MAC is more often a known phone rotating its address than a new device
+	if newDevice.Randomized {
+		previous, err := s.rotatedFrom(ctx, &newDevice)
+		if err != nil {
+			return nil, err
+		}
+		if previous != nil {
+			err = s.linkRandomized(ctx, &newDevice, previous)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		err = s.NotificationClient.SendNotification(fmt.Sprintf("New Device in %s (%s)", newDevice.Home, newDevice.Id.Ip), fmt.Sprintf("%s (%s)", newDevice.Name, newDevice.Manufacturer), newDevice.Home)
+		if err != nil {
+			s.Logger.Error(fmt.Sprintf("Error sending notification: %s", err.Error()))
+		}
 	}
+	s.publishEvent(&pb.PresenceEvent{Type: pb.PresenceEventType_NEW_DEVICE_DISCOVERED, Home: newDevice.Home, Device: &newDevice})
 	s.RegisterMetric(&newDevice)
-	return nil
+	return &newDevice, nil
 }
 
 func (s *Server) existingDevice(ctx context.Context, houseDevice *pb.Devices, incoming *pb.AddressRequest, home string) error {
@@ -604,6 +682,7 @@ func (s *Server) existingDevice(ctx context.Context, houseDevice *pb.Devices, in
 	}
 	if incoming.Mac != "" {
 		houseDevice.Id.Mac = incoming.Mac
+		houseDevice.Randomized = randomizedMac(incoming.Mac)
 	}
 
 	if incoming.Mac == "" && incoming.Ip == houseDevice.Id.UUID {
@@ -615,12 +694,14 @@ func (s *Server) existingDevice(ctx context.Context, houseDevice *pb.Devices, in
 	}
 
 	if home != houseDevice.Home {
+		previousHome := houseDevice.Home
 		houseDevice.Home = home
 		message := fmt.Sprintf("%s has moved to %s", houseDevice.Name, houseDevice.Home)
 		err := s.NotificationClient.SendNotification(houseDevice.Home, message, houseDevice.Home)
 		if err != nil {
 			return err
 		}
+		s.publishEvent(&pb.PresenceEvent{Type: pb.PresenceEventType_DEVICE_MOVED_HOME, Home: home, PreviousHome: previousHome, Device: houseDevice})
 	}
 	// this is all handled via ttl now and leases
 	//if houseDevice.GetPerson() {
@@ -630,6 +711,7 @@ func (s *Server) existingDevice(ctx context.Context, houseDevice *pb.Devices, in
 	//	}
 	//}
 	houseDevice.LastSeen = int64(time.Now().Unix())
+	houseDevice.Away = false
 	houseDevice.Latency = incoming.GetDistance()
 	if len(incoming.Hosts) > 0 {
 		hostnamesMaps := map[string]bool{}
@@ -643,10 +725,20 @@ func (s *Server) existingDevice(ctx context.Context, houseDevice *pb.Devices, in
 			}
 		}
 	}
+	for _, service := range incoming.GetServices() {
+		if !slices.Contains(houseDevice.Services, service) {
+			houseDevice.Services = append(houseDevice.Services, service)
+		}
+	}
+	s.alertOpenPorts(houseDevice, updateOpenPorts(houseDevice, incoming))
+	if incoming.GetOs() != nil {
+		houseDevice.OS = incoming.GetOs()
+	}
+	s.classify(houseDevice)
 	if incoming.Mac != "" && incoming.Mac == houseDevice.Id.Mac {
 		err := s.WriteNetworkDevice(ctx, houseDevice)
 		if err != nil {
-			s.Logger.Info("Error saving to ETCD: %s", err.Error())
+			s.Logger.Error(fmt.Sprintf("Error saving to store: %s", err.Error()))
 		}
 		s.RegisterMetric(houseDevice)
 	}
@@ -654,66 +746,19 @@ func (s *Server) existingDevice(ctx context.Context, houseDevice *pb.Devices, in
 }
 
 func (s *Server) GrantLease(ctx context.Context, data map[string]string, ttl int64) error {
-	key, leaseId, err := s.EtcdClient.GrantLease(ctx, data["home"], data["mac"], ttl)
-	if err != nil {
-		return err
-	}
-
-	if leaseId != nil && key != "" {
-		_, err = s.Kv.Put(ctx, key, data["value"], etcdv3.WithLease(*leaseId))
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// searchForOverlappingDevices Checks if reported device
-func (s *Server) searchForOverlappingDevices(in *pb.AddressRequest, home string) (*bool, error) {
-	devices, err := s.ReadNetworkConfig()
-	if err != nil {
-		return nil, err
-	}
-	in.Mac = in.Ip
-	found := false
-	for _, v := range devices {
-		// on same home and same ip, report it as the one with a mac
-		if v.Id.Ip == in.Ip && v.Home == home && strings.Contains(v.Id.Mac, ":") {
-			in.Mac = v.Id.Mac
-			found = true
-			return &found, err
-		}
-	}
-	return &found, err
+	return s.Store.GrantLease(ctx, data["home"], data["mac"], data["value"], ttl)
 }
 
 func (s *Server) ListPeopleRequest(ctx context.Context) (*pb.PeopleResponse, error) {
-	var people []*pb.People
-	resp, err := s.Kv.Get(ctx, peoplePrefix, etcdv3.WithPrefix())
+	humans, err := s.presence(ctx)
 	if err != nil {
 		return nil, err
 	}
-	for _, person := range resp.Kvs {
-		var human pb.People
-		err := yaml.Unmarshal(person.Value, &human)
-		if err != nil {
-			return nil, err
-		}
-		for _, device := range human.GetIds() {
-			resp, err := s.Kv.Get(ctx, fmt.Sprintf("%s%s", AlivePrefix, device))
-			if err != nil {
-				return nil, err
-			}
-			if len(resp.Kvs) > 0 {
-				for _, deviceId := range resp.Kvs {
-					human.Home = string(deviceId.Value)
-					human.Away = false
-
-				}
-			}
-		}
-		people = append(people, &human)
+	people := make([]*pb.People, 0, len(humans))
+	for _, human := range humans {
+		people = append(people, human)
 	}
+	sort.Slice(people, func(i, j int) bool { return people[i].GetName() < people[j].GetName() })
 	return &pb.PeopleResponse{People: people}, nil
 }
 
@@ -734,50 +779,46 @@ func (s *Server) ProcessIncomingAddress(ctx context.Context, in *pb.AddressReque
 	md := []*pb.Metadata{{Key: "type", Value: typeOfDevice}}
 
 	if incoming.Mac == "" && home != "" {
-		incoming.Mac = fmt.Sprintf("%s/%s", home, strings.ReplaceAll(in.Ip, ".", "_"))
-	}
-	opts := []etcdv3.OpOption{
-		etcdv3.WithLimit(1),
+		id, err := s.resolveIdentity(ctx, in, home)
+		if err != nil {
+			return nil, err
+		}
+		incoming.Mac = id
+		if id == "" {
+			incoming.Mac = ipOnlyId(home, in.Ip)
+		}
 	}
-	opts = append(opts, etcdv3.WithLastRev()...)
-
-	item, err := s.Kv.Get(ctx, fmt.Sprintf("%s%s", devicesPrefix, in.Mac), opts...)
+	exDevice, err := s.Store.GetDevice(ctx, in.Mac)
 	if err != nil {
 		return nil, err
 	}
 	path := "device"
-	if item.Count == 0 {
-		if *newDeviceIsPerson {
-			path = "person"
-		}
-		err := s.newDevice(ctx, in, home, md)
+	if exDevice == nil {
+		device, err := s.newDevice(ctx, in, home, md)
 		if err != nil {
 			return nil, err
 		}
+		if device.GetPerson() {
+			path = "person"
+		}
 		// grant lease after update for new person
-		err = s.GrantLease(ctx, map[string]string{"mac": in.Mac, "home": home, "value": path}, *TimeAwaySeconds)
+		err = s.GrantLease(ctx, map[string]string{"mac": in.Mac, "home": home, "value": path}, awayTimeout(device))
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		strDevice := item.Kvs[0].Value
-		var exDevice *pb.Devices
-		err = yaml.Unmarshal(strDevice, &exDevice)
-		if err != nil {
-			return nil, err
+		if lateReport(ctx, exDevice.GetReported()) {
+			return &pb.Reply{Acknowledged: true}, nil
 		}
-		if received := ctx.Value("received"); received != nil {
-			iReceived := received.(int64)
-			if exDevice.GetLastSeen() > iReceived {
-				return &pb.Reply{Acknowledged: true}, nil
-			}
+		if received := reportedAt(ctx); received > 0 {
+			exDevice.Reported = received
 		}
 		exDevice.Metadata = md
 		if exDevice.GetPerson() {
 			path = "person"
 		}
 		// grant lease before update for existing person
-		err = s.GrantLease(ctx, map[string]string{"mac": in.Mac, "home": home, "value": path}, *TimeAwaySeconds)
+		err = s.GrantLease(ctx, map[string]string{"mac": in.Mac, "home": home, "value": path}, awayTimeout(exDevice))
 		if err != nil {
 			return nil, err
 		}
@@ -786,6 +827,11 @@ func (s *Server) ProcessIncomingAddress(ctx context.Context, in *pb.AddressReque
 			return nil, err
 		}
 	}
+	// the ip only record of the device is history of the one known by MAC
+	err = s.mergeIpOnly(ctx, in.Mac, home, in.Ip)
+	if err != nil {
+		return nil, err
+	}
 	return &pb.Reply{Acknowledged: true}, nil
 }
 func (s *Server) grpcHitsMetrics(ctx context.Context, name string, itemCount int) {
@@ -833,25 +879,8 @@ func (s *Server) grpcPrometheusMetrics(ctx context.Context, promMetric string, n
 }
 
 func (s *Server) getBLEById(id *string) (*pb.BleDevices, error) {
-	opts := []etcdv3.OpOption{
-		etcdv3.WithLimit(1),
-	}
 	s.Logger.Info(fmt.Sprintf("%s%s", BlesPrefix, *id))
-	item, err := s.Kv.Get(s.GetContext(), fmt.Sprintf("%s%s", BlesPrefix, *id), opts...)
-	if err != nil {
-		return nil, err
-	}
-	found := item.Count == 1
-	if found {
-		strDevice := item.Kvs[0].Value
-		var device *pb.BleDevices
-		err = yaml.Unmarshal(strDevice, &device)
-		if err != nil {
-			return nil, err
-		}
-		return device, nil
-	}
-	return nil, nil
+	return s.Store.GetBleDevice(s.GetContext(), *id)
 }
 
 func (s *Server) processIncomingBleAddress(ctx context.Context, in *pb.BleRequest) (*bool, error) {
@@ -863,7 +892,20 @@ func (s *Server) processIncomingBleAddress(ctx context.Context, in *pb.BleReques
 	if !found {
 		return &found, nil
 	}
+	if lateReport(ctx, device.GetReported()) {
+		return &found, nil
+	}
 	headers, _ := metadata.FromIncomingContext(ctx)
+	home := device.GetHome()
+	if val := headers.Get("home"); home == "" && len(val) > 0 {
+		home = val[0]
+	}
+	if home != "" {
+		err = s.GrantLease(ctx, map[string]string{"mac": device.GetId(), "home": home, "value": "device"}, bleAwayTimeout(device))
+		if err != nil {
+			return nil, err
+		}
+	}
 	md := []string{}
 	for _, item := range device.GetMetadata() {
 		if !slices.Contains(md, item.GetKey()) {
@@ -880,6 +922,9 @@ func (s *Server) processIncomingBleAddress(ctx context.Context, in *pb.BleReques
 	}
 	device.LastSeen = time.Now().Unix()
 	device.Distance = in.Distance
+	if received := reportedAt(ctx); received > 0 {
+		device.Reported = received
+	}
 
 	err = s.writeBleDevice(device)
 	if err != nil {
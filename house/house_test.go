@@ -0,0 +1,288 @@
+package house
+
+import (
+	"context"
+	"fmt"
+	"github.com/beaujr/nmap_prometheus/etcd"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/grpc/metadata"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingNotifier struct {
+	sync.Mutex
+	sent []string
+}
+
+func (n *recordingNotifier) SendNotification(title string, message string, topic string) error {
+	n.Lock()
+	defer n.Unlock()
+	n.sent = append(n.sent, fmt.Sprintf("%s|%s|%s", title, message, topic))
+	return nil
+}
+
+func (n *recordingNotifier) contains(substr string) bool {
+	n.Lock()
+	defer n.Unlock()
+	for _, item := range n.sent {
+		if strings.Contains(item, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+type recordingAssistant struct {
+	sync.Mutex
+	commands []string
+}
+
+func (a *recordingAssistant) Call(command string) (*string, error) {
+	a.Lock()
+	defer a.Unlock()
+	a.commands = append(a.commands, command)
+	return &command, nil
+}
+
+type recordingPublisher struct {
+	sync.Mutex
+	retained map[string]string
+}
+
+func (p *recordingPublisher) PublishRetained(topic string, payload []byte) error {
+	p.Lock()
+	defer p.Unlock()
+	p.retained[topic] = string(payload)
+	return nil
+}
+
+func (p *recordingPublisher) get(topic string) string {
+	p.Lock()
+	defer p.Unlock()
+	return p.retained[topic]
+}
+
+type testHarness struct {
+	*Server
+	mem       *etcd.Memory
+	notifier  *recordingNotifier
+	assistant *recordingAssistant
+}
+
+func newTestHarness(t testing.TB) *testHarness {
+	t.Helper()
+	mem := etcd.NewMemory()
+	notifier := &recordingNotifier{}
+	assistant := &recordingAssistant{}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s := NewCustomServer(ctx, NewEtcdStore(mem, NewEtcdLeaser(mem, mem), mem), assistant, notifier, slog.NewTextHandler(os.Stderr, nil))
+	return &testHarness{Server: s, mem: mem, notifier: notifier, assistant: assistant}
+}
+
+// eventually polls condition as the lease watcher handles events in the background
+func eventually(t *testing.T, condition func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func agentContext(home string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("home", home, "client", "test"))
+}
+
+// report sends in as the agent of home does and fails the test unless it is processed
+func (h *testHarness) report(t testing.TB, home string, in *pb.AddressRequest) {
+	t.Helper()
+	if _, err := h.ProcessIncomingAddress(agentContext(home), in); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// hasGauge reports whether the metrics of the device with mac are exported
+func (h *testHarness) hasGauge(mac string) bool {
+	h.gauges.Lock()
+	defer h.gauges.Unlock()
+	_, ok := h.gauges.items[mac]
+	return ok
+}
+
+func TestProcessIncomingAddressNewDevice(t *testing.T) {
+	h := newTestHarness(t)
+	in := &pb.AddressRequest{Ip: "192.168.1.5", Mac: "A8:BB:CC:DD:EE:01", Vendor: "Apple", Hosts: []string{"phone.lan"}}
+	h.report(t, "aus", in)
+	device, err := h.GetDevice("A8:BB:CC:DD:EE:01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetHome() != "aus" || device.GetManufacturer() != "Apple" || device.GetId().GetIp() != "192.168.1.5" {
+		t.Errorf("unexpected device stored: %v", device)
+	}
+	if !h.notifier.contains("New Device in aus") {
+		t.Errorf("expected new device notification, got %v", h.notifier.sent)
+	}
+	alive, err := h.Store.ListAlive(context.Background(), "aus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alive["aus/A8:BB:CC:DD:EE:01"] != "device" {
+		t.Errorf("expected device to be alive, got %v", alive)
+	}
+}
+
+func TestProcessIncomingAddressExistingDevice(t *testing.T) {
+	h := newTestHarness(t)
+	existing := &pb.Devices{
+		Id:        &pb.NetworkId{Ip: "192.168.1.5", Mac: "AA:BB:CC:DD:EE:02", UUID: "AA:BB:CC:DD:EE:02"},
+		Home:      "aus",
+		Name:      "phone",
+		Person:    true,
+		Hostnames: []string{"phone.lan"},
+	}
+	if err := h.WriteNetworkDevice(context.Background(), existing); err != nil {
+		t.Fatal(err)
+	}
+	in := &pb.AddressRequest{Ip: "192.168.1.6", Mac: "AA:BB:CC:DD:EE:02", Hosts: []string{"phone.lan", "phone.local"}}
+	h.report(t, "aus", in)
+	device, err := h.GetDevice("AA:BB:CC:DD:EE:02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetId().GetIp() != "192.168.1.6" {
+		t.Errorf("expected ip to be updated, got %s", device.GetId().GetIp())
+	}
+	if len(device.GetHostnames()) != 2 {
+		t.Errorf("expected hostnames to be merged, got %v", device.GetHostnames())
+	}
+	if h.notifier.contains("New Device") {
+		t.Errorf("unexpected new device notification: %v", h.notifier.sent)
+	}
+	if h.IsHouseEmpty(context.Background(), "aus") {
+		t.Error("expected house to be occupied")
+	}
+}
+
+func TestDeviceAwayTimeout(t *testing.T) {
+	h := newTestHarness(t)
+	desktop := &pb.Devices{
+		Id:          &pb.NetworkId{Ip: "192.168.1.11", Mac: "AA:BB:CC:DD:EE:07", UUID: "AA:BB:CC:DD:EE:07"},
+		Home:        "aus",
+		Person:      true,
+		AwayTimeout: 120,
+	}
+	if _, err := h.UpdateDevice(context.Background(), desktop); err != nil {
+		t.Fatal(err)
+	}
+	in := &pb.AddressRequest{Ip: "192.168.1.11", Mac: "AA:BB:CC:DD:EE:07"}
+	h.report(t, "aus", in)
+	h.mem.Advance(119 * time.Second)
+	if h.IsHouseEmpty(context.Background(), "aus") {
+		t.Fatal("expected house to be occupied inside the device timeout")
+	}
+	h.mem.Advance(2 * time.Second)
+	if !h.IsHouseEmpty(context.Background(), "aus") {
+		t.Error("expected house to be empty after the device timeout rather than -timeout")
+	}
+
+	// a timeout changed while the device is leased applies from its next report
+	h.report(t, "aus", in)
+	stored, err := h.GetDevice("AA:BB:CC:DD:EE:07")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored.AwayTimeout = 600
+	if _, err := h.UpdateDevice(context.Background(), stored); err != nil {
+		t.Fatal(err)
+	}
+	h.report(t, "aus", in)
+	h.mem.Advance(121 * time.Second)
+	if h.IsHouseEmpty(context.Background(), "aus") {
+		t.Fatal("expected the new device timeout to replace the one the lease was granted with")
+	}
+	h.mem.Advance(480 * time.Second)
+	if !h.IsHouseEmpty(context.Background(), "aus") {
+		t.Error("expected house to be empty after the new device timeout")
+	}
+}
+
+func TestUpdateDeviceMerges(t *testing.T) {
+	h := newTestHarness(t)
+	phone := &pb.Devices{
+		Id:        &pb.NetworkId{Ip: "192.168.1.12", Mac: "AA:BB:CC:DD:EE:08", UUID: "AA:BB:CC:DD:EE:08"},
+		Home:      "aus",
+		Name:      "phone",
+		Person:    true,
+		Hostnames: []string{"phone.lan"},
+	}
+	if _, err := h.UpdateDevice(context.Background(), phone); err != nil {
+		t.Fatal(err)
+	}
+	body := `{"Id":{"UUID":"AA:BB:CC:DD:EE:08"},"AwayTimeout":900}`
+	w := httptest.NewRecorder()
+	h.Devices(w, httptest.NewRequest(http.MethodPost, "/devices", strings.NewReader(body)))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d %s", w.Code, w.Body)
+	}
+	device, err := h.GetDevice("AA:BB:CC:DD:EE:08")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetAwayTimeout() != 900 || device.GetName() != "phone" || !device.GetPerson() || device.GetId().GetIp() != "192.168.1.12" || len(device.GetHostnames()) != 1 {
+		t.Errorf("expected the fields left out to be kept, got %v", device)
+	}
+}
+
+func TestOSDetection(t *testing.T) {
+	h := newTestHarness(t)
+	linux := &pb.OSMatch{Name: "Linux 4.15 - 5.8", Family: "Linux", Generation: "4.X", Vendor: "Linux", Accuracy: 96}
+	for _, in := range []*pb.AddressRequest{
+		{Ip: "10.0.4.1", Mac: "A8:BB:CC:DD:EE:40", Os: linux, PortsScanned: true},
+		{Ip: "10.0.4.1", Mac: "A8:BB:CC:DD:EE:40"},
+	} {
+		h.report(t, "aus", in)
+	}
+	device, err := h.GetDevice("A8:BB:CC:DD:EE:40")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetOS().GetAccuracy() != 96 {
+		t.Errorf("expected the OS to outlive a ping scan, got %v", device.GetOS())
+	}
+	if label := osLabel(device.GetOS()); label != "Linux 4.X" {
+		t.Errorf("expected os label Linux 4.X, got %q", label)
+	}
+	if label := osLabel(nil); label != "" {
+		t.Errorf("expected a blank os label without detection, got %q", label)
+	}
+}
+
+// BenchmarkProcessIncomingAddress measures a report of a known device while 500 devices hold alive leases
+func BenchmarkProcessIncomingAddress(b *testing.B) {
+	h := newTestHarness(b)
+	ctx := agentContext("aus")
+	reports := make([]*pb.AddressRequest, 500)
+	for i := range reports {
+		reports[i] = &pb.AddressRequest{Ip: fmt.Sprintf("10.0.%d.%d", i/250, i%250), Mac: fmt.Sprintf("AA:BB:CC:DD:%02X:%02X", i/256, i%256), Vendor: "bench"}
+		if _, err := h.ProcessIncomingAddress(ctx, reports[i]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := h.ProcessIncomingAddress(ctx, reports[i%len(reports)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package house
+
+import (
+	"context"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// keepAlive is how often an idle /events connection is written to so proxies keep it open
+const keepAlive = 30 * time.Second
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// eventsRequest reads the home and type filters and the resume cursor of an /events request,
+// the cursor is the lastEventId parameter or the Last-Event-ID header browsers send on reconnect
+func eventsRequest(req *http.Request) (*pb.WatchPresenceRequest, error) {
+	query := req.URL.Query()
+	request := &pb.WatchPresenceRequest{Home: query.Get("home"), Type: query.Get("type")}
+	cursor := query.Get("lastEventId")
+	if cursor == "" {
+		cursor = req.Header.Get("Last-Event-ID")
+	}
+	if cursor != "" {
+		lastEventId, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lastEventId: %s", cursor)
+		}
+		request.LastEventId = lastEventId
+	}
+	return request, nil
+}
+
+// Events API endpoint streaming presence events as Server-Sent Events or over a WebSocket
+func (s *Server) Events(w http.ResponseWriter, req *http.Request) {
+	request, err := eventsRequest(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if websocket.IsWebSocketUpgrade(req) {
+		s.websocketEvents(w, req, request)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	events := s.events.subscribe(req.Context(), request.GetLastEventId())
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !matches(request, event) {
+				continue
+			}
+			data, err := protojson.Marshal(event)
+			if err != nil {
+				s.Logger.Error(err.Error())
+				continue
+			}
+			_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.GetId(), event.GetType().String(), data)
+			if err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// websocketEvents writes each presence event matching request as a JSON text message
+func (s *Server) websocketEvents(w http.ResponseWriter, req *http.Request, request *pb.WatchPresenceRequest) {
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		// Upgrade has already replied with the error
+		s.Logger.Error(err.Error())
+		return
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	// nothing is expected from the client, reading notices it closing
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	events := s.events.subscribe(ctx, request.GetLastEventId())
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !matches(request, event) {
+				continue
+			}
+			data, err := protojson.Marshal(event)
+			if err != nil {
+				s.Logger.Error(err.Error())
+				continue
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(10*time.Second)); err != nil {
+				return
+			}
+		}
+	}
+}
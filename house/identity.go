@@ -0,0 +1,76 @@
+package house
+
+import (
+	"context"
+	"crypto/x509"
+	"github.com/beaujr/nmap_prometheus/certs"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// clientCertificate returns the verified client certificate of the call or nil
+func clientCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
+
+// bindIdentity sets the client and home headers from the client certificate, a
+// call claiming to be another agent or home than its certificate is rejected
+func bindIdentity(ctx context.Context) (context.Context, error) {
+	cert := clientCertificate(ctx)
+	if cert == nil {
+		return ctx, nil
+	}
+	home, agent := certs.Identity(cert)
+	headers, _ := metadata.FromIncomingContext(ctx)
+	headers = headers.Copy()
+	for key, value := range map[string]string{"client": agent, "home": home} {
+		if value == "" {
+			continue
+		}
+		if val := headers.Get(key); len(val) > 0 && val[0] != value {
+			return nil, status.Errorf(codes.PermissionDenied, "%s %s does not match the certificate %s", key, val[0], cert.Subject.CommonName)
+		}
+		headers.Set(key, value)
+	}
+	return metadata.NewIncomingContext(ctx, headers), nil
+}
+
+// identityStream is a ServerStream with the identity bound context
+type identityStream struct {
+	gogrpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryIdentityInterceptor binds the agent identity of unary calls to their client certificate
+func UnaryIdentityInterceptor(ctx context.Context, req interface{}, info *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (interface{}, error) {
+	ctx, err := bindIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamIdentityInterceptor binds the agent identity of streams to their client certificate
+func StreamIdentityInterceptor(srv interface{}, ss gogrpc.ServerStream, info *gogrpc.StreamServerInfo, handler gogrpc.StreamHandler) error {
+	ctx, err := bindIdentity(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+}
@@ -1,35 +1,240 @@
 package house
 
 import (
-	"crypto/tls"
+	"context"
+	"flag"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 )
 
-// GetManufacturer to get the vendor of the device
+var (
+	macVendorsAPI      = flag.Bool("macVendorsAPI", false, "Look up MACs missing from the -ouiDatabase on api.macvendors.com")
+	macVendorsInterval = flag.Int64("macVendorsInterval", 1000, "Milliseconds between api.macvendors.com requests")
+	vendorCacheTTL     = flag.Int64("vendorCacheTTL", 30*24*3600, "Seconds a vendor found on api.macvendors.com is cached in the store")
+	vendorMissTTL      = flag.Int64("vendorMissTTL", 24*3600, "Seconds an OUI api.macvendors.com does not know is cached in the store")
+	vendorRefresh      = flag.Int64("vendorRefresh", 3600, "Seconds between looking up the vendors of devices still unknown")
+)
+
+// macVendorsURL is the api.macvendors.com lookup, formatted with the OUI
+var macVendorsURL = "https://api.macvendors.com/%s"
+
+// macVendorsClient is used for the api.macvendors.com fallback
+var macVendorsClient = &http.Client{Timeout: 10 * time.Second}
+
+// GetManufacturer to get the vendor of the device from the -ouiDatabase, nil when it is not in it
 func GetManufacturer(mac string) (*string, error) {
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
-	client := &http.Client{Transport: tr}
-	requestURL := fmt.Sprintf("https://api.macvendors.com/%s", mac)
-	req, _ := http.NewRequest("GET", requestURL, nil)
-	res, err := client.Do(req)
+	db, err := loadOUI()
 	if err != nil {
 		return nil, err
 	}
+	if vendor, ok := db.Lookup(mac); ok {
+		return &vendor, nil
+	}
+	return nil, nil
+}
 
+// ouiOf returns the MA-L part of mac the online lookups are cached by
+func ouiOf(mac string) string {
+	digits := macHex(mac)
+	if len(digits) < 6 {
+		return digits
+	}
+	return digits[:6]
+}
+
+// lookupMacVendors asks api.macvendors.com for the vendor of mac, blank when it does not know it
+func lookupMacVendors(ctx context.Context, mac string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(macVendorsURL, ouiOf(mac)), nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := macVendorsClient.Do(req)
+	if err != nil {
+		return "", err
+	}
 	defer res.Body.Close()
-	if res.StatusCode == 429 {
-		return nil, fmt.Errorf("MacVendors time out: %s", mac)
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", nil
+	case http.StatusTooManyRequests:
+		return "", fmt.Errorf("MacVendors time out: %s", mac)
+	default:
+		return "", fmt.Errorf("MacVendors returned %s for %s", res.Status, mac)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
 	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// vendorQueue holds the MACs waiting for api.macvendors.com, one per OUI
+type vendorQueue struct {
+	sync.Mutex
+	pending map[string]bool
+	macs    chan string
+}
+
+func newVendorQueue() *vendorQueue {
+	return &vendorQueue{pending: make(map[string]bool), macs: make(chan string, 256)}
+}
+
+// enqueue queues mac unless its OUI is already queued, a full queue drops it
+// for the next vendor refresh rather than blocking the caller
+func (q *vendorQueue) enqueue(mac string) {
+	oui := ouiOf(mac)
+	q.Lock()
+	defer q.Unlock()
+	if q.pending[oui] {
+		return
+	}
+	select {
+	case q.macs <- mac:
+		q.pending[oui] = true
+	default:
+	}
+}
+
+func (q *vendorQueue) done(mac string) {
+	q.Lock()
+	defer q.Unlock()
+	delete(q.pending, ouiOf(mac))
+}
 
-	body, err := ioutil.ReadAll(res.Body)
+// vendorOf returns the vendor of mac from the -ouiDatabase or the vendors cached in the store,
+// nil when neither knows it yet. Unknown MACs are queued for api.macvendors.com with -macVendorsAPI
+func (s *Server) vendorOf(ctx context.Context, mac string) (*string, error) {
+	vendor, err := GetManufacturer(mac)
+	if vendor != nil || err != nil {
+		return vendor, err
+	}
+	cached, found, err := s.Store.GetVendor(ctx, ouiOf(mac))
 	if err != nil {
 		return nil, err
 	}
-	vendor := string(body)
-	return &vendor, nil
+	if found && cached != "" {
+		return &cached, nil
+	}
+	if !found && *macVendorsAPI {
+		s.vendors.enqueue(mac)
+	}
+	return nil, nil
+}
+
+// resolveVendors looks up the queued MACs on api.macvendors.com, at most one every -macVendorsInterval
+func resolveVendors(server *Server) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(*macVendorsInterval) * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-server.GetContext().Done():
+				return
+			case mac := <-server.vendors.macs:
+				select {
+				case <-server.GetContext().Done():
+					return
+				case <-ticker.C:
+				}
+				err := server.resolveVendor(server.GetContext(), mac)
+				server.vendors.done(mac)
+				if err != nil {
+					server.Logger.Error(err.Error())
+				}
+			}
+		}
+	}()
+}
+
+// resolveVendor looks up mac online, caches the answer and sets it on the devices of the OUI
+func (s *Server) resolveVendor(ctx context.Context, mac string) error {
+	oui := ouiOf(mac)
+	vendor, found, err := s.Store.GetVendor(ctx, oui)
+	if err != nil {
+		return err
+	}
+	if !found {
+		vendor, err = lookupMacVendors(ctx, mac)
+		if err != nil {
+			return err
+		}
+		ttl := *vendorCacheTTL
+		if vendor == "" {
+			ttl = *vendorMissTTL
+		}
+		err = s.Store.PutVendor(ctx, oui, vendor, ttl)
+		if err != nil {
+			return err
+		}
+	}
+	if vendor == "" {
+		return nil
+	}
+	devices, err := s.Store.ListDevices(ctx)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if knownVendor(device.GetManufacturer()) || !hasMac(device) || ouiOf(device.GetId().GetMac()) != oui {
+			continue
+		}
+		err = s.setVendor(ctx, device.GetId().GetUUID(), vendor)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setVendor stores the vendor found for the device with the UUID, a name that was only its MAC becomes
+// the vendor. The device is read again as reports may have updated it since the devices were listed
+func (s *Server) setVendor(ctx context.Context, id string, vendor string) error {
+	device, err := s.Store.GetDevice(ctx, id)
+	if err != nil || device == nil || knownVendor(device.GetManufacturer()) {
+		return err
+	}
+	if device.GetName() == strings.ReplaceAll(device.GetId().GetMac(), ":", "_") {
+		device.Name = vendor
+	}
+	device.Manufacturer = vendor
+	s.classify(device)
+	s.Logger.Info(fmt.Sprintf("Vendor of %s is %s", device.GetId().GetUUID(), vendor))
+	err = s.WriteNetworkDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+	s.RegisterMetric(device)
+	return nil
+}
+
+// refreshVendors looks up the vendors of the devices still unknown in the -ouiDatabase and the
+// cache, the ones neither knows are queued again for api.macvendors.com
+func (s *Server) refreshVendors(ctx context.Context) error {
+	devices, err := s.Store.ListDevices(ctx)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		mac := device.GetId().GetMac()
+		if knownVendor(device.GetManufacturer()) || !hasMac(device) || device.GetRandomized() || randomizedMac(mac) {
+			continue
+		}
+		vendor, err := s.vendorOf(ctx, mac)
+		if err != nil {
+			return err
+		}
+		if vendor == nil {
+			continue
+		}
+		err = s.setVendor(ctx, device.GetId().GetUUID(), *vendor)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
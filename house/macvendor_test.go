@@ -0,0 +1,88 @@
+package house
+
+import (
+	"context"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestVendorLookupQueue(t *testing.T) {
+	var requests []string
+	var lock sync.Mutex
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		requests = append(requests, req.URL.Path)
+		lock.Unlock()
+		if req.URL.Path != "/0050C2" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Write([]byte("Acme Corp"))
+	}))
+	defer api.Close()
+	defer func(url string, enabled bool, interval int64) {
+		macVendorsURL, *macVendorsAPI, *macVendorsInterval = url, enabled, interval
+	}(macVendorsURL, *macVendorsAPI, *macVendorsInterval)
+	macVendorsURL, *macVendorsAPI, *macVendorsInterval = api.URL+"/%s", true, 10
+	h := newTestHarness(t)
+	ctx := context.Background()
+
+	for _, mac := range []string{"00:50:C2:00:00:01", "00:50:C2:00:00:02", "9C:9C:9C:00:00:01"} {
+		h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.1.1", Mac: mac})
+	}
+	eventually(t, func() bool {
+		for _, mac := range []string{"00:50:C2:00:00:01", "00:50:C2:00:00:02"} {
+			device, err := h.Store.GetDevice(ctx, mac)
+			if err != nil || device.GetManufacturer() != "Acme Corp" {
+				return false
+			}
+		}
+		_, found, err := h.Store.GetVendor(ctx, "9C9C9C")
+		return err == nil && found
+	}, "expected the queued vendors to be looked up and cached")
+	if err := h.refreshVendors(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if len(requests) != 2 {
+		t.Errorf("expected one request per OUI and the unknown one cached, got %v", requests)
+	}
+	if device, err := h.Store.GetDevice(ctx, "9C:9C:9C:00:00:01"); err != nil || device.GetManufacturer() != "unknown" {
+		t.Errorf("expected the unknown OUI to stay unknown, got %v %v", device, err)
+	}
+}
+
+// vendorErrorStore fails vendor cache reads
+type vendorErrorStore struct {
+	Store
+}
+
+func (v vendorErrorStore) GetVendor(context.Context, string) (string, bool, error) {
+	return "", false, fmt.Errorf("vendor cache unavailable")
+}
+
+func TestVendorLookupErrorRetried(t *testing.T) {
+	h := newTestHarness(t)
+	ctx := context.Background()
+	store := h.Store
+	h.Store = vendorErrorStore{store}
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.1.1", Mac: "9C:9C:9C:00:00:01"})
+	h.Store = store
+	if device, err := h.Store.GetDevice(ctx, "9C:9C:9C:00:00:01"); err != nil || device.GetManufacturer() != "unknown" {
+		t.Fatalf("expected a failed lookup to leave the vendor unknown, got %v %v", device, err)
+	}
+	if err := h.Store.PutVendor(ctx, "9C9C9C", "Acme Corp", 3600); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.refreshVendors(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if device, err := h.Store.GetDevice(ctx, "9C:9C:9C:00:00:01"); err != nil || device.GetManufacturer() != "Acme Corp" {
+		t.Errorf("expected the vendor to be looked up again, got %v %v", device, err)
+	}
+}
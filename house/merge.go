@@ -0,0 +1,236 @@
+package house
+
+import (
+	"context"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ipOnlyId is the UUID given to a device reported in home without a MAC
+func ipOnlyId(home, ip string) string {
+	return fmt.Sprintf("%s/%s", home, strings.ReplaceAll(ip, ".", "_"))
+}
+
+// hasMac reports whether the device was identified by a MAC rather than its ip
+func hasMac(device *pb.Devices) bool {
+	return strings.Contains(device.GetId().GetMac(), ":")
+}
+
+// knownVendor reports whether vendor was looked up rather than left blank or unknown
+func knownVendor(vendor string) bool {
+	return vendor != "" && vendor != "unknown"
+}
+
+// vendorsDiffer reports whether both vendors are known and not the same
+func vendorsDiffer(a, b string) bool {
+	return knownVendor(a) && knownVendor(b) && !strings.EqualFold(a, b)
+}
+
+// sameIdentity reports whether the ip only sighting in home is device. A shared hostname is
+// enough while a shared ip, which DHCP hands on to other devices, needs the vendor to agree too
+// and a different known vendor always rules the device out
+func sameIdentity(in *pb.AddressRequest, home string, device *pb.Devices) bool {
+	if device.GetHome() != home || !hasMac(device) || vendorsDiffer(in.GetVendor(), device.GetManufacturer()) {
+		return false
+	}
+	for _, host := range in.GetHosts() {
+		if slices.ContainsFunc(device.GetHostnames(), func(known string) bool { return strings.EqualFold(known, host) }) {
+			return true
+		}
+	}
+	return in.GetIp() != "" && device.GetId().GetIp() == in.GetIp() &&
+		in.GetVendor() != "" && strings.EqualFold(in.GetVendor(), device.GetManufacturer())
+}
+
+// resolveIdentity returns the UUID of the known MAC device an ip only sighting in home is,
+// or blank when none or more than one device matches it
+func (s *Server) resolveIdentity(ctx context.Context, in *pb.AddressRequest, home string) (string, error) {
+	devices, err := s.Store.FindDevices(ctx, home, in.GetIp(), in.GetHosts())
+	if err != nil {
+		return "", err
+	}
+	match := ""
+	for _, device := range devices {
+		if !sameIdentity(in, home, device) {
+			continue
+		}
+		if match != "" {
+			return "", nil
+		}
+		match = device.GetId().GetUUID()
+	}
+	return match, nil
+}
+
+// mergeInto folds source into target keeping what target already knows, the newest
+// sighting wins for the ip and last seen
+func mergeInto(target, source *pb.Devices) {
+	if target.GetName() == "" || target.GetName() == target.GetId().GetUUID() {
+		target.Name = source.GetName()
+	}
+	if !knownVendor(target.GetManufacturer()) {
+		target.Manufacturer = source.GetManufacturer()
+	}
+	if target.GetCommand() == "" {
+		target.Command = source.GetCommand()
+	}
+	if target.GetAwayTimeout() == 0 {
+		target.AwayTimeout = source.GetAwayTimeout()
+	}
+	target.Person = target.GetPerson() || source.GetPerson()
+	target.Smart = target.GetSmart() || source.GetSmart()
+	target.PresenceAware = target.GetPresenceAware() || source.GetPresenceAware()
+	for _, host := range source.GetHostnames() {
+		if !slices.Contains(target.Hostnames, host) {
+			target.Hostnames = append(target.Hostnames, host)
+		}
+	}
+	for _, item := range source.GetMetadata() {
+		if metadataValue(target.GetMetadata(), item.GetKey()) == "" {
+			target.Metadata = append(target.Metadata, item)
+		}
+	}
+	if source.GetLastSeen() > target.GetLastSeen() {
+		target.LastSeen = source.GetLastSeen()
+		target.Away = source.GetAway()
+		target.Home = source.GetHome()
+		if source.GetId().GetIp() != "" {
+			target.Id.Ip = source.GetId().GetIp()
+		}
+	}
+}
+
+// mergeDevices folds the sources into target, moves them to targets owner and deletes them
+func (s *Server) mergeDevices(ctx context.Context, target *pb.Devices, sources ...*pb.Devices) error {
+	people, err := s.Store.ListPeople(ctx)
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		mergeInto(target, source)
+		for _, human := range people {
+			if !slices.Contains(human.GetIds(), source.GetId().GetUUID()) {
+				continue
+			}
+			human.Ids = slices.DeleteFunc(human.Ids, func(id string) bool { return id == source.GetId().GetUUID() })
+			if !slices.Contains(human.Ids, target.GetId().GetUUID()) {
+				human.Ids = append(human.Ids, target.GetId().GetUUID())
+			}
+			err = s.Store.PutPerson(ctx, human)
+			if err != nil {
+				return err
+			}
+		}
+		s.Logger.Info(fmt.Sprintf("Merged %s into %s", source.GetId().GetUUID(), target.GetId().GetUUID()))
+	}
+	err = s.WriteNetworkDevice(ctx, target)
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		err = s.movePresence(ctx, target, source)
+		if err != nil {
+			return err
+		}
+		err = s.deleteDeviceById(source.GetId().GetUUID())
+		if err != nil {
+			return err
+		}
+		err = s.forgetDevice(ctx, source)
+		if err != nil {
+			return err
+		}
+	}
+	s.RegisterMetric(target)
+	return nil
+}
+
+// movePresence grants target a lease when the merged source is alive and target is not,
+// so deleting the alive key of the source does not empty the home
+func (s *Server) movePresence(ctx context.Context, target, source *pb.Devices) error {
+	alive, err := s.Store.ListAlive(ctx, source.GetHome())
+	if err != nil {
+		return err
+	}
+	if _, ok := alive[path.Join(source.GetHome(), source.GetId().GetUUID())]; !ok {
+		return nil
+	}
+	if _, ok := alive[path.Join(target.GetHome(), target.GetId().GetUUID())]; ok {
+		return nil
+	}
+	value := "device"
+	if target.GetPerson() {
+		value = "person"
+	}
+	return s.Store.GrantLease(ctx, target.GetHome(), target.GetId().GetUUID(), value, awayTimeout(target))
+}
+
+// recentlyShared reports whether the ip only source is still the device holding its ip, it shares
+// a hostname with target or was seen within its away timeout, before DHCP could hand the ip on
+func recentlyShared(target, source *pb.Devices) bool {
+	for _, host := range source.GetHostnames() {
+		if slices.ContainsFunc(target.GetHostnames(), func(known string) bool { return strings.EqualFold(known, host) }) {
+			return true
+		}
+	}
+	return time.Now().Unix()-source.GetLastSeen() <= awayTimeout(source)
+}
+
+// mergeIpOnly folds the ip only record of ip in home into the device with the UUID once it is known by MAC,
+// a record of another vendor or a stale one without a shared hostname is a different device that had the ip before
+func (s *Server) mergeIpOnly(ctx context.Context, id, home, ip string) error {
+	if ip == "" {
+		return nil
+	}
+	source, err := s.Store.GetDevice(ctx, ipOnlyId(home, ip))
+	if err != nil || source == nil {
+		return err
+	}
+	target, err := s.Store.GetDevice(ctx, id)
+	if err != nil || target == nil || target.GetId().GetUUID() == source.GetId().GetUUID() {
+		return err
+	}
+	if vendorsDiffer(target.GetManufacturer(), source.GetManufacturer()) || !recentlyShared(target, source) {
+		return nil
+	}
+	return s.mergeDevices(ctx, target, source)
+}
+
+// mergeDeviceIds folds the devices with the source UUIDs into the one with the target UUID
+func (s *Server) mergeDeviceIds(ctx context.Context, request *pb.MergeDevicesRequest) (*pb.Devices, error) {
+	if request.GetTarget() == "" || len(request.GetSources()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "target and sources are required")
+	}
+	target, err := s.Store.GetDevice(ctx, request.GetTarget())
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, status.Errorf(codes.NotFound, "unknown device: %s", request.GetTarget())
+	}
+	sources := make([]*pb.Devices, 0, len(request.GetSources()))
+	for _, id := range request.GetSources() {
+		if id == request.GetTarget() {
+			return nil, status.Error(codes.InvalidArgument, "a device can not be merged into itself")
+		}
+		source, err := s.Store.GetDevice(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil {
+			return nil, status.Errorf(codes.NotFound, "unknown device: %s", id)
+		}
+		sources = append(sources, source)
+	}
+	err = s.mergeDevices(ctx, target, sources...)
+	if err != nil {
+		return nil, err
+	}
+	return target, nil
+}
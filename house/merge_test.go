@@ -0,0 +1,115 @@
+package house
+
+import (
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestIdentityMerging(t *testing.T) {
+	h := newTestHarness(t)
+	ctx := context.Background()
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.7", Hosts: []string{"laptop.lan"}})
+	if err := h.Store.PutPerson(ctx, &pb.People{Name: "sam", Ids: []string{"aus/10_0_0_7"}}); err != nil {
+		t.Fatal(err)
+	}
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.7", Mac: "AA:BB:CC:DD:EE:10", Vendor: "Dell"})
+	if device, err := h.Store.GetDevice(ctx, "aus/10_0_0_7"); err != nil || device != nil {
+		t.Fatalf("expected the ip only record to be merged, got %v %v", device, err)
+	}
+	laptop, err := h.GetDevice("AA:BB:CC:DD:EE:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(laptop.GetHostnames()) != 1 || laptop.GetHostnames()[0] != "laptop.lan" {
+		t.Errorf("expected the hostnames of the ip only record to be kept, got %v", laptop)
+	}
+	sam, err := h.Store.GetPerson(ctx, "sam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sam.GetIds()) != 1 || sam.GetIds()[0] != "AA:BB:CC:DD:EE:10" {
+		t.Errorf("expected sam to own the merged device, got %v", sam)
+	}
+
+	// DHCP moved the laptop, the hostname still identifies it
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.9", Hosts: []string{"laptop.lan"}})
+	if device, err := h.Store.GetDevice(ctx, "aus/10_0_0_9"); err != nil || device != nil {
+		t.Fatalf("expected the sighting to resolve to the laptop, got %v %v", device, err)
+	}
+	if laptop, err = h.GetDevice("AA:BB:CC:DD:EE:10"); err != nil || laptop.GetId().GetIp() != "10.0.0.9" {
+		t.Errorf("expected the laptop to move to 10.0.0.9, got %v %v", laptop, err)
+	}
+	// a different vendor is not the laptop
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.9", Vendor: "Sonos"})
+	if device, err := h.Store.GetDevice(ctx, "aus/10_0_0_9"); err != nil || device == nil {
+		t.Fatalf("expected a separate ip only record, got %v %v", device, err)
+	}
+	// nor is a sighting that only shares the ip, DHCP may have handed it on
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.20", Mac: "AA:BB:CC:DD:EE:11", Vendor: "HP"})
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.20"})
+	if device, err := h.Store.GetDevice(ctx, "aus/10_0_0_20"); err != nil || device == nil {
+		t.Fatalf("expected an ip alone not to identify a device, got %v %v", device, err)
+	}
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.20", Vendor: "HP"})
+	if device, err := h.Store.GetDevice(ctx, "AA:BB:CC:DD:EE:11"); err != nil || device == nil || len(device.GetHostnames()) != 0 {
+		t.Fatalf("expected the ip and vendor to identify the printer, got %v %v", device, err)
+	}
+
+	// a stale ip only record is whoever had the ip before unless a hostname says otherwise
+	stale := time.Now().Unix() - *TimeAwaySeconds - 60
+	for _, ip := range []string{"10.0.0.30", "10.0.0.31"} {
+		h.report(t, "aus", &pb.AddressRequest{Ip: ip, Hosts: []string{"host-" + ip}})
+		device, err := h.Store.GetDevice(ctx, ipOnlyId("aus", ip))
+		if err != nil || device == nil {
+			t.Fatalf("expected an ip only record of %s, got %v %v", ip, device, err)
+		}
+		device.LastSeen = stale
+		if err := h.Store.PutDevice(ctx, device); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.30", Mac: "AA:BB:CC:DD:EE:12"})
+	if device, err := h.Store.GetDevice(ctx, "aus/10_0_0_30"); err != nil || device == nil {
+		t.Fatalf("expected a stale ip only record not to be merged, got %v %v", device, err)
+	}
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.31", Mac: "AA:BB:CC:DD:EE:13", Hosts: []string{"host-10.0.0.31"}})
+	if device, err := h.Store.GetDevice(ctx, "aus/10_0_0_31"); err != nil || device != nil {
+		t.Fatalf("expected a stale ip only record with the hostname to be merged, got %v %v", device, err)
+	}
+
+	_, err = h.MergeDevices(ctx, &pb.MergeDevicesRequest{Target: "AA:BB:CC:DD:EE:10", Sources: []string{"AA:BB:CC:DD:EE:10"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected merging a device into itself to be rejected, got %v", err)
+	}
+	_, err = h.MergeDevices(ctx, &pb.MergeDevicesRequest{Target: "AA:BB:CC:DD:EE:10", Sources: []string{"aus/10_0_0_99"}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected an unknown source to be rejected, got %v", err)
+	}
+	merged, err := h.MergeDevices(ctx, &pb.MergeDevicesRequest{Target: "AA:BB:CC:DD:EE:10", Sources: []string{"aus/10_0_0_9"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.GetManufacturer() != "Dell" {
+		t.Errorf("expected the target to keep its vendor, got %v", merged)
+	}
+	if device, err := h.Store.GetDevice(ctx, "aus/10_0_0_9"); err != nil || device != nil {
+		t.Errorf("expected the source to be deleted, got %v %v", device, err)
+	}
+	alive, err := h.Store.ListAlive(ctx, "aus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := alive["aus/aus/10_0_0_9"]; ok {
+		t.Errorf("expected the alive key of the source to be deleted, got %v", alive)
+	}
+	if _, ok := alive["aus/AA:BB:CC:DD:EE:10"]; !ok {
+		t.Errorf("expected the target to stay alive, got %v", alive)
+	}
+	if h.hasGauge("aus/10_0_0_9") {
+		t.Error("expected the metrics of the source to be removed")
+	}
+}
@@ -0,0 +1,128 @@
+package house
+
+import (
+	"context"
+	"fmt"
+	"github.com/beaujr/nmap_prometheus/broker"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"strings"
+	"time"
+)
+
+// SubscribeMQTT subscribes client to the agents reports, it is the OnConnectHandler
+// for broker.Connect so subscriptions are restored after a reconnect
+func (s *Server) SubscribeMQTT(client mqtt.Client) {
+	token := client.SubscribeMultiple(map[string]byte{
+		broker.Subscription(broker.AddressesTopic): 1,
+		broker.Subscription(broker.BleTopic):       1,
+	}, s.handleMQTT)
+	if !token.WaitTimeout(30 * time.Second) {
+		s.Logger.Error("timed out subscribing to mqtt")
+		return
+	}
+	if token.Error() != nil {
+		s.Logger.Error(token.Error().Error())
+	}
+}
+
+// handleMQTT processes the reports of the agent in the topic. The broker authenticates agents,
+// its ACLs are what stop one agent publishing to the topic of another home or agent
+func (s *Server) handleMQTT(_ mqtt.Client, msg mqtt.Message) {
+	home, id, kind, err := broker.ParseTopic(msg.Topic())
+	if err != nil {
+		s.Logger.Error(err.Error())
+		return
+	}
+	agent := &pb.MQTTAgent{Home: home, Id: id}
+	switch kind {
+	case broker.AddressesTopic:
+		err = s.processMQTTAddresses(agent, msg.Payload())
+	case broker.BleTopic:
+		err = s.processMQTTBle(agent, msg.Payload())
+	}
+	if err != nil {
+		s.Logger.Error(err.Error())
+	}
+}
+
+// checkMQTTAgent rejects a payload claiming to be from another agent than its topic
+func checkMQTTAgent(topic, payload *pb.MQTTAgent) error {
+	if payload == nil || (payload.GetHome() == topic.GetHome() && payload.GetId() == topic.GetId()) {
+		return nil
+	}
+	return fmt.Errorf("agent %s/%s published to the topic of %s/%s", payload.GetHome(), payload.GetId(), topic.GetHome(), topic.GetId())
+}
+
+// receivedKey is the context key of the agent's timestamp of an MQTT report
+type receivedKey struct{}
+
+// reportedAt returns the agent's timestamp of the report in ctx, 0 for gRPC calls
+func reportedAt(ctx context.Context) int64 {
+	received, _ := ctx.Value(receivedKey{}).(int64)
+	return received
+}
+
+// lateReport reports whether the report in ctx was scanned before the one reported last,
+// both are agent timestamps so a server clock ahead of the agent's drops nothing
+func lateReport(ctx context.Context, reported int64) bool {
+	received := reportedAt(ctx)
+	return received > 0 && reported > received
+}
+
+// mqttContext returns the context a gRPC call from the agent would have, with
+// the time the agent scanned so late messages are ignored. The home
+// and client come from the topic, the payload's metadata cannot override them
+func (s *Server) mqttContext(agent *pb.MQTTAgent, md []*pb.Metadata, timestamp int64) context.Context {
+	pairs := []string{"home", agent.GetHome(), "client", agent.GetId()}
+	for _, item := range md {
+		if key := strings.ToLower(item.GetKey()); key == "home" || key == "client" || key == "apikey" {
+			continue
+		}
+		pairs = append(pairs, item.GetKey(), item.GetValue())
+	}
+	ctx := metadata.NewIncomingContext(s.GetContext(), metadata.Pairs(pairs...))
+	if timestamp > 0 {
+		ctx = context.WithValue(ctx, receivedKey{}, timestamp)
+	}
+	return ctx
+}
+
+func (s *Server) processMQTTAddresses(agent *pb.MQTTAgent, payload []byte) error {
+	in := &pb.MQTTAddressRequest{}
+	err := proto.Unmarshal(payload, in)
+	if err != nil {
+		return err
+	}
+	err = checkMQTTAgent(agent, in.GetAgent())
+	if err != nil {
+		return err
+	}
+	ctx := s.mqttContext(agent, in.GetMetadata(), in.GetTimestamp())
+	s.grpcHitsMetrics(ctx, "MQTTAddresses", len(in.GetAddresses()))
+	for _, address := range in.GetAddresses() {
+		_, err = s.ProcessIncomingAddress(ctx, address)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) processMQTTBle(agent *pb.MQTTAgent, payload []byte) error {
+	in := &pb.MQTTBleRequest{}
+	err := proto.Unmarshal(payload, in)
+	if err != nil {
+		return err
+	}
+	err = checkMQTTAgent(agent, in.GetAgent())
+	if err != nil {
+		return err
+	}
+	ctx := s.mqttContext(agent, in.GetMetadata(), in.GetTimestamp())
+	s.grpcHitsMetrics(ctx, "MQTTAck", 1)
+	_, err = s.processIncomingBleAddress(ctx, in.GetBles())
+	return err
+}
@@ -0,0 +1,63 @@
+package house
+
+import (
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/protobuf/proto"
+	"testing"
+	"time"
+)
+
+func TestMQTTAddressesIgnoreLateMessages(t *testing.T) {
+	h := newTestHarness(t)
+	report := func(ip string, timestamp int64) {
+		t.Helper()
+		payload, err := proto.Marshal(&pb.MQTTAddressRequest{
+			Agent:     &pb.MQTTAgent{Home: "aus", Id: "lounge-pi"},
+			Addresses: []*pb.AddressRequest{{Ip: ip, Mac: "AA:BB:CC:DD:EE:09"}},
+			Timestamp: timestamp,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := h.processMQTTAddresses(&pb.MQTTAgent{Home: "aus", Id: "lounge-pi"}, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	payload, err := proto.Marshal(&pb.MQTTAddressRequest{
+		Agent:     &pb.MQTTAgent{Home: "aus", Id: "lounge-pi"},
+		Addresses: []*pb.AddressRequest{{Ip: "192.168.1.16", Mac: "AA:BB:CC:DD:EE:19"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.processMQTTAddresses(&pb.MQTTAgent{Home: "syd", Id: "garage-pi"}, payload); err == nil {
+		t.Error("expected a payload from another agent than its topic to be rejected")
+	}
+	// the agent's clock runs an hour behind the server's
+	agentNow := time.Now().Unix() - 3600
+	report("192.168.1.13", agentNow)
+	report("192.168.1.14", agentNow-60)
+	device, err := h.GetDevice("AA:BB:CC:DD:EE:09")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetHome() != "aus" || device.GetId().GetIp() != "192.168.1.13" {
+		t.Errorf("expected the late message to be ignored, got %v", device)
+	}
+	report("192.168.1.15", agentNow)
+	device, err = h.GetDevice("AA:BB:CC:DD:EE:09")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetId().GetIp() != "192.168.1.15" {
+		t.Errorf("expected a message from the same second to update the ip, got %s", device.GetId().GetIp())
+	}
+	report("192.168.1.16", agentNow+1)
+	device, err = h.GetDevice("AA:BB:CC:DD:EE:09")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetId().GetIp() != "192.168.1.16" || device.GetReported() != agentNow+1 {
+		t.Errorf("expected the newer message to update the ip, got %v", device)
+	}
+}
@@ -2,13 +2,17 @@ package house
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	pb "github.com/beaujr/nmap_prometheus/proto"
-	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"log"
+	"net/url"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -45,111 +49,180 @@ func writeNetworkDevices(devices map[string]*pb.Devices) error {
 }
 
 func (s *Server) WriteNetworkDevice(ctx context.Context, item *pb.Devices) error {
-	d1, err := yaml.Marshal(item)
+	err := s.Store.PutDevice(ctx, item)
 	if err != nil {
-		log.Fatalf(err.Error())
+		return err
 	}
-
-	key := fmt.Sprintf("%s%s", devicesPrefix, item.Id.UUID)
-	_, err = s.Kv.Put(ctx, key, string(d1))
-	return err
+	s.publishDeviceTracker(item)
+	return nil
 }
+
 func (s *Server) ReadNetworkConfig() (map[string]*pb.Devices, error) {
-	var result map[string]*pb.Devices
-	result = make(map[string]*pb.Devices)
-	items, err := s.Kv.Get(s.GetContext(), devicesPrefix, clientv3.WithPrefix())
+	return s.Store.ListDevices(s.GetContext())
+}
+
+func (s *Server) getDevices(ctx context.Context) ([]*pb.Devices, error) {
+	items, err := s.Store.ListDevices(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if items == nil {
-		return result, nil
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]*pb.Devices, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, items[key])
 	}
-	i := 0
-	for i < int(items.Count) {
-		val := items.Kvs[i].Value
-		key := items.Kvs[i].Key
-		var dev *pb.Devices
-		err = yaml.Unmarshal(val, &dev)
+	return result, nil
+}
+
+// scanBatch is how many devices are read from the store at a time while filling a page
+const scanBatch = 100
+
+// metadataValue returns the value of key in items or blank
+func metadataValue(items []*pb.Metadata, key string) string {
+	for _, item := range items {
+		if item.GetKey() == key {
+			return item.GetValue()
+		}
+	}
+	return ""
+}
+
+// matchesDevice reports whether device passes the filters of request
+func matchesDevice(request *pb.ListDevicesRequest, device *pb.Devices) bool {
+	switch {
+	case request.GetHome() != "" && request.GetHome() != device.GetHome():
+		return false
+	case request.Person != nil && request.GetPerson() != device.GetPerson():
+		return false
+	case request.Away != nil && request.GetAway() != device.GetAway():
+		return false
+	case request.GetType() != "" && request.GetType() != metadataValue(device.GetMetadata(), "type"):
+		return false
+	case request.GetManufacturer() != "" && !strings.Contains(strings.ToLower(device.GetManufacturer()), strings.ToLower(request.GetManufacturer())):
+		return false
+	}
+	return true
+}
+
+// devicesQuery reads the ListDevicesRequest from the home, person, away, type, manufacturer,
+// page_size and page_token query parameters
+func devicesQuery(query url.Values) (*pb.ListDevicesRequest, error) {
+	request := &pb.ListDevicesRequest{
+		Home:         query.Get("home"),
+		Type:         query.Get("type"),
+		Manufacturer: query.Get("manufacturer"),
+		PageToken:    query.Get("page_token"),
+	}
+	for name, field := range map[string]**bool{"person": &request.Person, "away": &request.Away} {
+		if query.Get(name) == "" {
+			continue
+		}
+		value, err := strconv.ParseBool(query.Get(name))
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid %s: %s", name, query.Get(name))
 		}
-		strKey := string(key)
-		newKey := strings.ReplaceAll(strKey, devicesPrefix, "")
-		result[string(newKey)] = dev
-		i++
+		*field = &value
 	}
-	return result, nil
+	if query.Get("page_size") != "" {
+		size, err := strconv.ParseInt(query.Get("page_size"), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page_size: %s", query.Get("page_size"))
+		}
+		request.PageSize = int32(size)
+	}
+	return request, nil
 }
 
-func (s *Server) getDevices(ctx context.Context) ([]*pb.Devices, error) {
-	var result []*pb.Devices
-	result = make([]*pb.Devices, 0)
-	//leadCtx := clientv3.WithRequireLeader(ctx)
-	items, err := s.Kv.Get(ctx, devicesPrefix, clientv3.WithPrefix())
-	if err != nil {
-		return nil, err
+// listDevices returns the devices matching request ordered by their store key, a page ends with
+// the token of its last key so the next one carries on from there even as devices change
+func (s *Server) listDevices(ctx context.Context, request *pb.ListDevicesRequest) (*pb.DevicesResponse, error) {
+	if request.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
 	}
-	if items == nil {
-		return nil, nil
+	after, err := base64.RawURLEncoding.DecodeString(request.GetPageToken())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_token: %s", request.GetPageToken())
 	}
-	i := 0
-	for i < int(items.Count) {
-		val := items.Kvs[i].Value
-		var dev *pb.Devices
-		err = yaml.Unmarshal(val, &dev)
+	last := string(after)
+	response := &pb.DevicesResponse{Devices: make([]*pb.Devices, 0)}
+	for {
+		keys, batch, more, err := s.Store.ScanDevices(ctx, last, scanBatch)
 		if err != nil {
 			return nil, err
 		}
-		result = append(result, dev)
-		i++
+		for i, device := range batch {
+			last = keys[i]
+			if !matchesDevice(request, device) {
+				continue
+			}
+			response.Devices = append(response.Devices, device)
+			if int32(len(response.Devices)) == request.GetPageSize() {
+				if more || i < len(batch)-1 {
+					response.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+				}
+				return response, nil
+			}
+		}
+		if !more {
+			return response, nil
+		}
 	}
-	return result, nil
 }
 
 func (s *Server) GetDevice(id string) (*pb.Devices, error) {
-	items, err := s.Kv.Get(s.GetContext(), fmt.Sprintf("%s%s", devicesPrefix, id))
+	dev, err := s.Store.GetDevice(s.GetContext(), id)
 	if err != nil {
 		return nil, err
 	}
-	if items == nil {
-		return nil, nil
-	}
-	if items.Count != 1 {
+	if dev == nil {
 		return nil, fmt.Errorf("coulnt find distinct item for: %s", id)
 	}
-	val := items.Kvs[0].Value
-	var dev *pb.Devices
-	err = yaml.Unmarshal(val, &dev)
-	if err != nil {
-		return nil, err
-	}
 	return dev, nil
 }
 
 func (s *Server) deleteDeviceById(id string) error {
-	_, err := s.Kv.Delete(s.GetContext(), fmt.Sprintf("%s%s", devicesPrefix, id))
+	err := s.Store.DeleteDevice(s.GetContext(), id)
 	if err != nil {
 		return err
 	}
-	return nil
+	if s.homeAssistant != nil {
+		err = s.homeAssistant.untrack(haObjectId("device", id))
+	}
+	return err
+}
+
+// forgetDevice stops exporting the metrics of a deleted device and removes its alive key
+func (s *Server) forgetDevice(ctx context.Context, device *pb.Devices) error {
+	s.forgetGauge(device.GetId().GetMac())
+	return s.Store.DeleteAlive(ctx, device.GetHome(), device.GetId().GetUUID())
+}
+
+// forgetGauge stops exporting the metrics of the network device with mac, ble gauges are kept
+func (s *Server) forgetGauge(mac string) {
+	s.gauges.Lock()
+	defer s.gauges.Unlock()
+	if _, ok := s.gauges.items[mac].(*networkDeviceGauge); ok {
+		delete(s.gauges.items, mac)
+	}
 }
 
 func (s *Server) processPerson(houseDevice *pb.Devices) error {
-	homeKey := fmt.Sprintf("%s%s", HomePrefix, houseDevice.Home)
-	houseStatus, err := s.Kv.Get(s.GetContext(), homeKey)
+	homes, err := s.Store.ListHomes(s.GetContext())
 	if err != nil {
 		log.Panic(err.Error())
 	}
 
-	if houseStatus.Count == 0 {
-		homeKey := fmt.Sprintf("%s%s", HomePrefix, houseDevice.Home)
-		_, err = s.Kv.Put(s.GetContext(), homeKey, "false")
+	if empty, ok := homes[houseDevice.Home]; !ok {
+		err = s.Store.PutHome(s.GetContext(), houseDevice.Home, false)
 		if err != nil {
 			log.Panic(err.Error())
 		}
-	} else if val, err := strconv.ParseBool(string(houseStatus.Kvs[0].Value)); val && err == nil {
-		homeKey := fmt.Sprintf("%s%s", HomePrefix, houseDevice.Home)
-		_, err = s.Kv.Put(s.GetContext(), homeKey, "false")
+	} else if empty {
+		err = s.Store.PutHome(s.GetContext(), houseDevice.Home, false)
 		if err != nil {
 			log.Panic(err.Error())
 		}
@@ -175,34 +248,14 @@ func (s *Server) processPerson(houseDevice *pb.Devices) error {
 }
 
 func (s *Server) ReadHomesConfig() (map[string]*bool, error) {
-	var result map[string]*bool
-	result = make(map[string]*bool)
-	items, err := s.Kv.Get(s.GetContext(), HomePrefix, clientv3.WithPrefix())
+	homes, err := s.Store.ListHomes(s.GetContext())
 	if err != nil {
 		return nil, err
 	}
-	if items == nil {
-		return result, nil
-	}
-	i := 0
-	for i < int(items.Count) {
-		val := items.Kvs[i].Value
-		key := items.Kvs[i].Key
-		newKey := strings.ReplaceAll(string(key), HomePrefix, "")
-		boolVal, _ := strconv.ParseBool(string(val))
-		if strings.Contains(string(key), "//") {
-			key2 := strings.ReplaceAll(string(key), "//", "")
-			_, err := s.Kv.Put(s.GetContext(), key2, string(val))
-			if err != nil {
-				return nil, err
-			}
-			_, err = s.Kv.Delete(s.GetContext(), string(key))
-			if err != nil {
-				return nil, err
-			}
-		}
-		result[string(newKey)] = &boolVal
-		i++
+	result := make(map[string]*bool)
+	for key, val := range homes {
+		boolVal := val
+		result[key] = &boolVal
 	}
 	return result, nil
 }
@@ -0,0 +1,74 @@
+package house
+
+import (
+	"context"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestListDevicesPages(t *testing.T) {
+	stores := testStores(t)
+	// putAt stores a device under another key than its UUID
+	putAt := map[string]func(key string, device *pb.Devices) error{
+		"etcd": func(key string, device *pb.Devices) error {
+			return stores["etcd"].(*EtcdStore).put(context.Background(), devicesPrefix+key, device)
+		},
+		"bolt": func(key string, device *pb.Devices) error {
+			return stores["bolt"].(*BoltStore).put(devicesBucket, key, device)
+		},
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			s := NewCustomServer(ctx, store, &recordingAssistant{}, &recordingNotifier{}, slog.NewTextHandler(os.Stderr, nil))
+			for i, home := range []string{"aus", "nz", "aus", "aus", "nz", "aus"} {
+				mac := fmt.Sprintf("AA:BB:CC:DD:EE:%02d", i)
+				device := &pb.Devices{Id: &pb.NetworkId{Mac: mac, UUID: mac}, Home: home, Person: i%2 == 0}
+				if err := store.PutDevice(ctx, device); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// a record without a UUID must not send the pages back to the start
+			if err := putAt[name]("AA:BB:CC:DD:EE:01", &pb.Devices{Id: &pb.NetworkId{}, Home: "aus"}); err != nil {
+				t.Fatal(err)
+			}
+			pages := make([][]string, 0)
+			request := &pb.ListDevicesRequest{Home: "aus", PageSize: 2}
+			for {
+				response, err := s.ListDevices(ctx, request)
+				if err != nil {
+					t.Fatal(err)
+				}
+				page := make([]string, 0)
+				for _, device := range response.GetDevices() {
+					page = append(page, device.GetId().GetUUID())
+				}
+				pages = append(pages, page)
+				if response.GetNextPageToken() == "" {
+					break
+				}
+				request.PageToken = response.GetNextPageToken()
+			}
+			if fmt.Sprint(pages) != "[[AA:BB:CC:DD:EE:00 ] [AA:BB:CC:DD:EE:02 AA:BB:CC:DD:EE:03] [AA:BB:CC:DD:EE:05]]" {
+				t.Errorf("unexpected pages %v", pages)
+			}
+			person := false
+			response, err := s.ListDevices(ctx, &pb.ListDevicesRequest{Home: "aus", Person: &person})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(response.GetDevices()) != 3 || response.GetNextPageToken() != "" {
+				t.Errorf("expected the three aus devices that are not people, got %v", response)
+			}
+			if _, err := s.ListDevices(ctx, &pb.ListDevicesRequest{PageToken: "!"}); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected an invalid page token to be rejected, got %v", err)
+			}
+		})
+	}
+}
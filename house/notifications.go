@@ -4,7 +4,6 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	etcdv3 "go.etcd.io/etcd/client/v3"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -19,18 +18,18 @@ type Notifier interface {
 }
 
 // NewNotifier returns a new Notifier
-func NewNotifier(etcdClient etcdv3.KV) Notifier {
+func NewNotifier(store Store) Notifier {
 	if *debug || len(*fcmUrl) == 0 {
 		return &DebugNotifier{}
 	}
-	return &FCMNotifier{url: fcmUrl, etcdClient: etcdClient}
+	return &FCMNotifier{url: fcmUrl, store: store}
 }
 
 // FCMNotifier is an implementation of the Notifier
 type FCMNotifier struct {
 	Notifier
-	etcdClient etcdv3.KV
-	url        *string
+	store Store
+	url   *string
 }
 
 // DebugNotifier is a log implementation of the Notifier
@@ -39,24 +38,11 @@ type DebugNotifier struct {
 }
 
 func (fcm *FCMNotifier) getLastSentNotification() (*string, error) {
-	items, err := fcm.etcdClient.Get(context.Background(), fmt.Sprintf("%s%s", notificationsPrefix, "last"))
-	if err != nil {
-		return nil, err
-	}
-	if items == nil {
-		return nil, nil
-	}
-
-	if items.Count == 0 {
-		return nil, nil
-	}
-	lastMessage := string(items.Kvs[0].Value)
-	return &lastMessage, nil
-
+	return fcm.store.GetLastNotification(context.Background())
 }
 
 func (fcm *FCMNotifier) putLastSentNotification(notification string) error {
-	_, err := fcm.etcdClient.Put(context.Background(), fmt.Sprintf("%s%s", notificationsPrefix, "last"), notification)
+	err := fcm.store.PutLastNotification(context.Background(), notification)
 	if err != nil {
 		log.Println(err)
 		return err
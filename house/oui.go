@@ -0,0 +1,208 @@
+package house
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var ouiPath = flag.String("ouiDatabase", "config/oui.txt", "Path to the MAC vendor database written by admin update-oui, the embedded one is used until it exists")
+
+// embeddedOUI is the database shipped in the binary, the IEEE MA-L registry
+//
+//go:embed oui.txt
+var embeddedOUI string
+
+// registrationAuthority owns the MA-L blocks the MA-M and MA-S assignments are made from
+const registrationAuthority = "IEEE Registration Authority"
+
+// ouiLengths are the hex digits of the MA-S (36 bit), MA-M (28 bit) and MA-L (24 bit) assignments, longest first
+var ouiLengths = []int{9, 7, 6}
+
+// OUIDatabase maps the IEEE MA-L, MA-M and MA-S assignments to their vendor
+type OUIDatabase struct {
+	vendors map[string]string
+}
+
+// NewOUIDatabase returns an empty database
+func NewOUIDatabase() *OUIDatabase {
+	return &OUIDatabase{vendors: make(map[string]string)}
+}
+
+// macHex returns the hex digits of mac in upper case, separators are dropped
+func macHex(mac string) string {
+	return strings.ToUpper(strings.NewReplacer(":", "", "-", "", ".", "").Replace(mac))
+}
+
+// add assigns the prefix, given as hex digits, to vendor
+func (db *OUIDatabase) add(prefix, vendor string) error {
+	prefix = macHex(prefix)
+	if !slices.Contains(ouiLengths, len(prefix)) || strings.Trim(prefix, "0123456789ABCDEF") != "" {
+		return fmt.Errorf("invalid assignment: %s", prefix)
+	}
+	vendor = strings.TrimSpace(vendor)
+	// the MACs of a block split into MA-M and MA-S assignments are unknown without them
+	if vendor == registrationAuthority {
+		return nil
+	}
+	db.vendors[prefix] = vendor
+	return nil
+}
+
+// Len returns the number of assignments in the database
+func (db *OUIDatabase) Len() int {
+	return len(db.vendors)
+}
+
+// Lookup returns the vendor of the longest assignment mac falls in
+func (db *OUIDatabase) Lookup(mac string) (string, bool) {
+	digits := macHex(mac)
+	for _, length := range ouiLengths {
+		if len(digits) < length {
+			continue
+		}
+		if vendor, ok := db.vendors[digits[:length]]; ok {
+			return vendor, true
+		}
+	}
+	return "", false
+}
+
+// ReadOUI reads a database written by WriteTo, one "<hex prefix>\t<vendor>" per line
+func ReadOUI(r io.Reader) (*OUIDatabase, error) {
+	db := NewOUIDatabase()
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		prefix, vendor, ok := strings.Cut(text, "\t")
+		if !ok {
+			return nil, fmt.Errorf("oui database line %d: expected a prefix and vendor", line)
+		}
+		err := db.add(prefix, vendor)
+		if err != nil {
+			return nil, fmt.Errorf("oui database line %d: %w", line, err)
+		}
+	}
+	return db, scanner.Err()
+}
+
+// AddIEEE adds the assignments in one of the IEEE registry CSVs (oui.csv, mam.csv or oui36.csv)
+// and returns how many were read
+func (db *OUIDatabase) AddIEEE(r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return 0, err
+	}
+	if len(header) < 3 || header[1] != "Assignment" || header[2] != "Organization Name" {
+		return 0, fmt.Errorf("not an IEEE registry CSV, header: %v", header)
+	}
+	count := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < 3 {
+			return count, fmt.Errorf("IEEE registry line %d: expected registry, assignment and organization", line)
+		}
+		err = db.add(record[1], record[2])
+		if err != nil {
+			return count, fmt.Errorf("IEEE registry line %d: %w", line, err)
+		}
+		count++
+	}
+}
+
+// WriteTo writes the database sorted by prefix in the format read by ReadOUI
+func (db *OUIDatabase) WriteTo(w io.Writer) (int64, error) {
+	prefixes := sortedKeys(db.vendors)
+	writer := bufio.NewWriter(w)
+	var written int64
+	for _, prefix := range prefixes {
+		n, err := fmt.Fprintf(writer, "%s\t%s\n", prefix, db.vendors[prefix])
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, writer.Flush()
+}
+
+var (
+	ouiOnce     sync.Once
+	ouiDatabase *OUIDatabase
+	ouiErr      error
+)
+
+// loadOUI returns the -ouiDatabase, or the embedded database when the file does not exist
+func loadOUI() (*OUIDatabase, error) {
+	ouiOnce.Do(func() {
+		file, err := os.Open(*ouiPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			ouiDatabase, ouiErr = ReadOUI(strings.NewReader(embeddedOUI))
+			return
+		}
+		if err != nil {
+			ouiErr = err
+			return
+		}
+		defer file.Close()
+		ouiDatabase, ouiErr = ReadOUI(file)
+	})
+	return ouiDatabase, ouiErr
+}
+
+// UpdateOUIDatabase replaces the -ouiDatabase with the assignments in the IEEE registry CSVs
+// at paths and returns how many were written
+func UpdateOUIDatabase(paths ...string) (int, error) {
+	db := NewOUIDatabase()
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		_, err = db.AddIEEE(file)
+		file.Close()
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if db.Len() == 0 {
+		return 0, fmt.Errorf("no assignments in %s", strings.Join(paths, ", "))
+	}
+	// written next to the database and renamed so a failed update keeps the old one
+	tmp := *ouiPath + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	_, err = db.WriteTo(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	return db.Len(), os.Rename(tmp, *ouiPath)
+}
@@ -0,0 +1,74 @@
+package house
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const ieeeCSV = `Registry,Assignment,Organization Name,Organization Address
+MA-L,70B3D5,IEEE Registration Authority,"445 Hoes Lane Piscataway NJ US 08554 "
+MA-L,B827EB,Raspberry Pi Foundation,Mitchell Wood House Caldecote Cambridgeshire GB CB23 7NU
+`
+
+const ieeeMAMCSV = `Registry,Assignment,Organization Name,Organization Address
+MA-M,70B3D51,"Example Sensors, Inc.",Somewhere US
+`
+
+func TestOUIDatabase(t *testing.T) {
+	db := NewOUIDatabase()
+	for _, registry := range []string{ieeeCSV, ieeeMAMCSV} {
+		if _, err := db.AddIEEE(strings.NewReader(registry)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := db.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	db, err := ReadOUI(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for mac, expected := range map[string]string{
+		"70:B3:D5:1A:00:01": "Example Sensors, Inc.",
+		"70:B3:D5:2A:00:01": "",
+		"b8-27-eb-00-00-01": "Raspberry Pi Foundation",
+		"00:00:00:00:00:01": "",
+	} {
+		if vendor, _ := db.Lookup(mac); vendor != expected {
+			t.Errorf("%s: expected %q, got %q", mac, expected, vendor)
+		}
+	}
+	if _, err := db.AddIEEE(strings.NewReader("mac,vendor\nB827EB,pi\n")); err == nil {
+		t.Error("expected a file that is not an IEEE registry to be rejected")
+	}
+
+	embedded, err := ReadOUI(strings.NewReader(embeddedOUI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vendor, _ := embedded.Lookup("DC:A6:32:00:00:01"); vendor != "Raspberry Pi Trading Ltd" {
+		t.Errorf("expected the embedded database to know the Raspberry Pi, got %q", vendor)
+	}
+	if embedded.Len() < 30000 {
+		t.Errorf("expected the embedded database to hold the MA-L registry, got %d assignments", embedded.Len())
+	}
+
+	dir := t.TempDir()
+	registry := filepath.Join(dir, "oui.csv")
+	if err := os.WriteFile(registry, []byte(ieeeCSV), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path string) { *ouiPath = path }(*ouiPath)
+	*ouiPath = filepath.Join(dir, "oui.txt")
+	if count, err := UpdateOUIDatabase(registry); err != nil || count != 1 {
+		t.Fatalf("expected 1 assignment to be written, got %d %v", count, err)
+	}
+	data, err := os.ReadFile(*ouiPath)
+	if err != nil || !strings.Contains(string(data), "B827EB\tRaspberry Pi Foundation") {
+		t.Errorf("unexpected database written: %s %v", data, err)
+	}
+}
@@ -2,48 +2,238 @@ package house
 
 import (
 	"context"
-	"fmt"
-	etcdv3 "go.etcd.io/etcd/client/v3"
-	"gopkg.in/yaml.v2"
-	"log"
-	"path/filepath"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"go.opentelemetry.io/otel/attribute"
+	api "go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"slices"
+	"sort"
+	"strings"
 )
 
-type people struct {
-	Id      string   `json:"id",yaml:"id"`
-	Devices []string `json:"devices",yaml:"devices"`
-	Name    string   `json:"name",yaml:"name"`
+// personGaugePrefix namespaces the people in the observable items
+const personGaugePrefix = "person/"
+
+type personGauge struct {
+	home           float64
+	name, homeName string
+	attrs          api.MeasurementOption
+}
+
+func (s *Server) writePerson(person *pb.People) error {
+	return s.Store.PutPerson(s.GetContext(), person)
 }
 
-func (s *Server) writePerson(person *people) error {
-	d1, err := yaml.Marshal(person)
+// presence returns everyone keyed by name with Home and Away set from the alive keys,
+// a person is home while any of their network or ble devices is alive. Alive devices
+// flagged Person that nobody owns are returned as a person named after the device
+func (s *Server) presence(ctx context.Context) (map[string]*pb.People, error) {
+	alive, err := s.Store.ListAlive(ctx, "")
 	if err != nil {
-		log.Fatalf(err.Error())
+		return nil, err
 	}
-
-	key := fmt.Sprintf("%s%s", peoplePrefix, person.Id)
-	_, err = s.Kv.Put(s.GetContext(), key, string(d1))
-	return err
+	homes := make(map[string]string)
+	for key, value := range alive {
+		event := aliveEvent(key, value, false)
+		homes[event.Mac] = event.Home
+	}
+	people, err := s.Store.ListPeople(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]bool)
+	for _, human := range people {
+		human.Away = true
+		for _, id := range human.GetIds() {
+			owned[id] = true
+			if home, ok := homes[id]; ok {
+				human.Home = home
+				human.Away = false
+			}
+		}
+	}
+	for key, value := range alive {
+		event := aliveEvent(key, value, false)
+		if value == "person" && !owned[event.Mac] {
+			people[event.Mac] = &pb.People{Name: event.Mac, Ids: []string{event.Mac}, Home: event.Home}
+		}
+	}
+	return people, nil
 }
 
 func (s *Server) GetPeopleInHouses(ctx context.Context, home string) ([]string, error) {
 	var people []string
-	items, err := s.Kv.Get(ctx, filepath.Join(AlivePrefix, home), etcdv3.WithPrefix())
+	humans, err := s.presence(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if items == nil {
-		return people, nil
-	}
-	i := 0
-	for i < int(items.Count) {
-		val := items.Kvs[i].Value
-		key := items.Kvs[i].Key
-		id := filepath.Base(string(key))
-		if string(val) == "person" {
-			people = append(people, id)
+	for name, human := range humans {
+		if !human.GetAway() && human.GetHome() == home {
+			people = append(people, name)
 		}
-		i++
 	}
+	sort.Strings(people)
 	return people, nil
 }
+
+// registerPeopleMetrics sets home_detector_person_home for everyone, people no
+// longer returned by presence are kept at 0 in their last home
+func (s *Server) registerPeopleMetrics(ctx context.Context) error {
+	humans, err := s.presence(ctx)
+	if err != nil {
+		return err
+	}
+	events := make([]*pb.PresenceEvent, 0)
+	s.gauges.Lock()
+	for key, val := range s.gauges.items {
+		if g, ok := val.(*personGauge); ok && strings.HasPrefix(key, personGaugePrefix) && humans[g.name] == nil {
+			if g.home == 1 {
+				events = append(events, &pb.PresenceEvent{Type: pb.PresenceEventType_PERSON_LEFT, Home: g.homeName, Person: &pb.People{Name: g.name, Home: g.homeName, Away: true}})
+			}
+			g.home = 0
+		}
+	}
+	for name, human := range humans {
+		home := float64(1)
+		if human.GetAway() {
+			home = 0
+		}
+		previous, ok := s.gauges.items[personGaugePrefix+name].(*personGauge)
+		if home == 1 && (!ok || previous.home == 0) {
+			events = append(events, &pb.PresenceEvent{Type: pb.PresenceEventType_PERSON_ARRIVED, Home: human.GetHome(), Person: human})
+		} else if home == 0 && ok && previous.home == 1 {
+			events = append(events, &pb.PresenceEvent{Type: pb.PresenceEventType_PERSON_LEFT, Home: previous.homeName, Person: human})
+		}
+		attrs := []attribute.KeyValue{
+			attribute.Key("person").String(name),
+			attribute.Key("home").String(human.GetHome()),
+		}
+		s.gauges.items[personGaugePrefix+name] = &personGauge{home: home, name: name, homeName: human.GetHome(), attrs: api.WithAttributes(attrs...)}
+	}
+	s.gauges.Unlock()
+	for _, event := range events {
+		s.publishEvent(event)
+	}
+	s.publishPersonTrackers(ctx, humans)
+	return nil
+}
+
+// syncHouseStatus toggles every home whose empty state no longer matches the people in it
+func (s *Server) syncHouseStatus(ctx context.Context) error {
+	homes, err := s.Store.ListHomes(ctx)
+	if err != nil {
+		return err
+	}
+	for home, empty := range homes {
+		if current := s.IsHouseEmpty(ctx, home); current != empty {
+			err = s.ToggleHouseStatus(home, current)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return s.registerPeopleMetrics(ctx)
+}
+
+// CreatePerson Handler for adding a person
+func (s *Server) CreatePerson(ctx context.Context, person *pb.People) (*pb.Reply, error) {
+	if person.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "person name is required")
+	}
+	existing, err := s.Store.GetPerson(ctx, person.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "person %s already exists", person.GetName())
+	}
+	return s.putPerson(ctx, person)
+}
+
+// UpdatePerson Handler for replacing a person
+func (s *Server) UpdatePerson(ctx context.Context, person *pb.People) (*pb.Reply, error) {
+	existing, err := s.Store.GetPerson(ctx, person.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, status.Errorf(codes.NotFound, "unknown person: %s", person.GetName())
+	}
+	return s.putPerson(ctx, person)
+}
+
+// putPerson stores person once every device they own exists and belongs to nobody else
+func (s *Server) putPerson(ctx context.Context, person *pb.People) (*pb.Reply, error) {
+	for _, id := range person.GetIds() {
+		err := s.releaseDevice(ctx, id, person.GetName())
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := s.Store.PutPerson(ctx, &pb.People{Name: person.GetName(), Ids: person.GetIds(), Home: person.GetHome()})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Reply{Acknowledged: true}, s.syncHouseStatus(ctx)
+}
+
+// DeletePerson Handler for removing a person, their devices are left untouched
+func (s *Server) DeletePerson(ctx context.Context, request *pb.StringRequest) (*pb.Reply, error) {
+	err := s.Store.DeletePerson(ctx, request.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	s.gauges.Lock()
+	delete(s.gauges.items, personGaugePrefix+request.GetKey())
+	s.gauges.Unlock()
+	s.removePersonTracker(request.GetKey())
+	return &pb.Reply{Acknowledged: true}, s.syncHouseStatus(ctx)
+}
+
+// AssignDevice Handler for giving a network or ble device to a person
+func (s *Server) AssignDevice(ctx context.Context, request *pb.AssignDeviceRequest) (*pb.Reply, error) {
+	person, err := s.Store.GetPerson(ctx, request.GetPerson())
+	if err != nil {
+		return nil, err
+	}
+	if person == nil {
+		return nil, status.Errorf(codes.NotFound, "unknown person: %s", request.GetPerson())
+	}
+	if !slices.Contains(person.GetIds(), request.GetId()) {
+		person.Ids = append(person.Ids, request.GetId())
+	}
+	return s.putPerson(ctx, person)
+}
+
+// releaseDevice checks id is a known device and removes it from anyone but owner
+func (s *Server) releaseDevice(ctx context.Context, id, owner string) error {
+	device, err := s.Store.GetDevice(ctx, id)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		ble, err := s.Store.GetBleDevice(ctx, id)
+		if err != nil {
+			return err
+		}
+		if ble == nil {
+			return status.Errorf(codes.NotFound, "unknown device: %s", id)
+		}
+	}
+	people, err := s.Store.ListPeople(ctx)
+	if err != nil {
+		return err
+	}
+	for name, human := range people {
+		if name == owner || !slices.Contains(human.GetIds(), id) {
+			continue
+		}
+		human.Ids = slices.DeleteFunc(human.Ids, func(item string) bool { return item == id })
+		err = s.Store.PutPerson(ctx, human)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
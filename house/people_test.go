@@ -0,0 +1,80 @@
+package house
+
+import (
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestPersonHomeWithAnyDevice(t *testing.T) {
+	h := newTestHarness(t)
+	ctx := context.Background()
+	laptop := &pb.Devices{Id: &pb.NetworkId{Ip: "192.168.1.12", Mac: "AA:BB:CC:DD:EE:08", UUID: "AA:BB:CC:DD:EE:08"}, Home: "aus"}
+	if err := h.WriteNetworkDevice(ctx, laptop); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Store.PutBleDevice(ctx, &pb.BleDevices{Id: "tile", Name: "keys", Home: "aus"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Store.PutHome(ctx, "aus", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.CreatePerson(ctx, &pb.People{Name: "beau", Ids: []string{"AA:BB:CC:DD:EE:08"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.AssignDevice(ctx, &pb.AssignDeviceRequest{Person: "beau", Id: "tile"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.AssignDevice(ctx, &pb.AssignDeviceRequest{Person: "beau", Id: "unknown"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected assigning an unknown device to be NotFound, got %v", err)
+	}
+	if _, err := h.AssignDevice(ctx, &pb.AssignDeviceRequest{Person: "alex", Id: "tile"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected assigning to an unknown person to be NotFound, got %v", err)
+	}
+	if _, err := h.UpdatePerson(ctx, &pb.People{Name: "alex"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected updating an unknown person to be NotFound, got %v", err)
+	}
+	if _, err := h.CreatePerson(ctx, &pb.People{Name: "beau"}); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected creating beau twice to be AlreadyExists, got %v", err)
+	}
+	if _, err := h.CreatePerson(ctx, &pb.People{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected a person without a name to be InvalidArgument, got %v", err)
+	}
+
+	if _, err := h.Ack(agentContext("aus"), &pb.BleRequest{Key: "tile"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, func() bool {
+		homes, err := h.ReadHomesConfig()
+		return err == nil && homes["aus"] != nil && !*homes["aus"]
+	}, "expected the ble device to bring beau home")
+	people, err := h.ListPeopleRequest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(people.GetPeople()) != 1 || people.GetPeople()[0].GetAway() || people.GetPeople()[0].GetHome() != "aus" {
+		t.Errorf("expected beau to be home in aus, got %v", people.GetPeople())
+	}
+	eventually(t, func() bool {
+		h.gauges.Lock()
+		defer h.gauges.Unlock()
+		g, ok := h.gauges.items[personGaugePrefix+"beau"].(*personGauge)
+		return ok && g.home == 1
+	}, "expected the person gauge to be home")
+
+	h.mem.Advance(time.Duration(*BleTimeAwaySeconds+1) * time.Second)
+	eventually(t, func() bool {
+		homes, err := h.ReadHomesConfig()
+		return err == nil && homes["aus"] != nil && *homes["aus"]
+	}, "expected aus to be empty once the ble lease expired")
+
+	if _, err := h.DeletePerson(ctx, &pb.StringRequest{Key: "beau"}); err != nil {
+		t.Fatal(err)
+	}
+	if person, err := h.Store.GetPerson(ctx, "beau"); err != nil || person != nil {
+		t.Errorf("expected beau to be deleted, got %v %v", person, err)
+	}
+}
@@ -0,0 +1,62 @@
+package house
+
+import (
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"slices"
+	"strings"
+	"time"
+)
+
+// samePort reports whether a and b are the same port and protocol
+func samePort(a, b *pb.OpenPort) bool {
+	return a.GetPort() == b.GetPort() && a.GetProtocol() == b.GetProtocol()
+}
+
+// describePort formats port like nmap eg 22/tcp ssh OpenSSH 8.9p1
+func describePort(port *pb.OpenPort) string {
+	return strings.TrimSpace(fmt.Sprintf("%d/%s %s %s", port.GetPort(), port.GetProtocol(), port.GetService(), port.GetBanner()))
+}
+
+// updateOpenPorts replaces the open ports of device with the ones a service scan found and returns
+// those never seen open on it before. A port a scan misses is not new when a later scan finds it again
+func updateOpenPorts(device *pb.Devices, incoming *pb.AddressRequest) []*pb.OpenPort {
+	if !incoming.GetPortsScanned() {
+		return nil
+	}
+	now := time.Now().Unix()
+	opened := make([]*pb.OpenPort, 0)
+	for _, port := range incoming.GetOpenPorts() {
+		same := func(seen *pb.OpenPort) bool { return samePort(seen, port) }
+		// devices stored before SeenPorts only have their OpenPorts
+		if i := slices.IndexFunc(device.GetSeenPorts(), same); i >= 0 {
+			port.FirstSeen = device.SeenPorts[i].GetFirstSeen()
+			continue
+		}
+		if i := slices.IndexFunc(device.GetOpenPorts(), same); i >= 0 {
+			port.FirstSeen = device.OpenPorts[i].GetFirstSeen()
+		} else {
+			port.FirstSeen = now
+			opened = append(opened, port)
+		}
+		device.SeenPorts = append(device.SeenPorts, port)
+	}
+	device.OpenPorts = incoming.GetOpenPorts()
+	return opened
+}
+
+// alertOpenPorts notifies the home of device about the ports opened on it
+func (s *Server) alertOpenPorts(device *pb.Devices, opened []*pb.OpenPort) {
+	if len(opened) == 0 {
+		return
+	}
+	descriptions := make([]string, 0, len(opened))
+	for _, port := range opened {
+		descriptions = append(descriptions, describePort(port))
+	}
+	s.Logger.Info(fmt.Sprintf("New ports open on %s: %s", device.GetId().GetUUID(), strings.Join(descriptions, ", ")))
+	err := s.NotificationClient.SendNotification(fmt.Sprintf("New port open on %s (%s)", device.GetName(), device.GetId().GetIp()), strings.Join(descriptions, "\n"), device.GetHome())
+	if err != nil {
+		s.Logger.Error(fmt.Sprintf("Error sending notification: %s", err.Error()))
+	}
+}
@@ -0,0 +1,39 @@
+package house
+
+import (
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"testing"
+)
+
+func TestOpenPortAlerts(t *testing.T) {
+	h := newTestHarness(t)
+	ssh := &pb.OpenPort{Port: 22, Protocol: "tcp", Service: "ssh", Banner: "OpenSSH 8.9p1"}
+	http := &pb.OpenPort{Port: 80, Protocol: "tcp", Service: "http"}
+	for _, in := range []*pb.AddressRequest{
+		{Ip: "10.0.3.1", Mac: "A8:BB:CC:DD:EE:30", Vendor: "Acme", OpenPorts: []*pb.OpenPort{ssh}, PortsScanned: true},
+		{Ip: "10.0.3.1", Mac: "A8:BB:CC:DD:EE:30"},
+		{Ip: "10.0.3.1", Mac: "A8:BB:CC:DD:EE:30", OpenPorts: []*pb.OpenPort{ssh}, PortsScanned: true},
+	} {
+		h.report(t, "aus", in)
+	}
+	if h.notifier.contains("New port open") {
+		t.Fatalf("expected no alert for ports already open, got %v", h.notifier.sent)
+	}
+	device, err := h.GetDevice("A8:BB:CC:DD:EE:30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(device.GetOpenPorts()) != 1 {
+		t.Errorf("expected a ping scan to leave the open ports alone, got %v", device.GetOpenPorts())
+	}
+	for _, ports := range [][]*pb.OpenPort{{ssh, http}, {http}, {ssh, http}} {
+		in := &pb.AddressRequest{Ip: "10.0.3.1", Mac: "A8:BB:CC:DD:EE:30", OpenPorts: ports, PortsScanned: true}
+		h.report(t, "aus", in)
+	}
+	if !h.notifier.contains("New port open on Acme (10.0.3.1)|80/tcp http|aus") || h.notifier.contains("22/tcp") {
+		t.Errorf("expected one alert for port 80 only, got %v", h.notifier.sent)
+	}
+	if len(h.notifier.sent) != 2 {
+		t.Errorf("expected a port a scan missed not to alert again, got %v", h.notifier.sent)
+	}
+}
@@ -0,0 +1,124 @@
+package house
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"slices"
+	"strings"
+	"time"
+)
+
+var randomizedWindow = flag.Int64("randomizedWindow", 3600, "Seconds a person device may have been gone for a new randomized MAC arriving after it to be linked to it")
+
+// randomizedMac reports whether mac is locally administered, the second bit of the first
+// octet is set on the MACs phones make up per network while vendor MACs never have it
+func randomizedMac(mac string) bool {
+	if !strings.Contains(mac, ":") {
+		return false
+	}
+	octet, err := hex.DecodeString(strings.SplitN(mac, ":", 2)[0])
+	if err != nil || len(octet) != 1 {
+		return false
+	}
+	// multicast addresses are never a device
+	return octet[0]&0x02 != 0 && octet[0]&0x01 == 0
+}
+
+// rotationScore rates how likely the new randomized device is candidate after a MAC rotation,
+// only person devices without a vendor (randomized MACs have none) are considered
+func rotationScore(device, candidate *pb.Devices, owned bool, now int64) int {
+	if candidate.GetHome() != device.GetHome() || candidate.GetId().GetUUID() == device.GetId().GetUUID() || !hasMac(candidate) {
+		return 0
+	}
+	if !candidate.GetPerson() && !owned {
+		return 0
+	}
+	if !candidate.GetRandomized() && !randomizedMac(candidate.GetId().GetMac()) && knownVendor(candidate.GetManufacturer()) {
+		return 0
+	}
+	score := 0
+	for _, host := range device.GetHostnames() {
+		if slices.Contains(candidate.GetHostnames(), host) {
+			score += 2
+			break
+		}
+	}
+	if candidate.GetId().GetIp() == device.GetId().GetIp() {
+		score++
+	}
+	// the old MAC stops being seen around when the new one arrives
+	if now-candidate.GetLastSeen() <= *randomizedWindow {
+		score++
+	}
+	return score
+}
+
+// rotatedFrom returns the person device the new randomized device is most likely a rotation of,
+// or nil when no single device matches with more than arrival timing
+func (s *Server) rotatedFrom(ctx context.Context, device *pb.Devices) (*pb.Devices, error) {
+	devices, err := s.Store.ListDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	people, err := s.Store.ListPeople(ctx)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[string]bool)
+	for _, human := range people {
+		for _, id := range human.GetIds() {
+			owned[id] = true
+		}
+	}
+	now := time.Now().Unix()
+	var best *pb.Devices
+	bestScore, tied := 1, false
+	for _, candidate := range devices {
+		score := rotationScore(device, candidate, owned[candidate.GetId().GetUUID()], now)
+		switch {
+		case score > bestScore:
+			best, bestScore, tied = candidate, score, false
+		case score == bestScore && best != nil:
+			tied = true
+		}
+	}
+	if tied {
+		return nil, nil
+	}
+	return best, nil
+}
+
+// linkRandomized carries what is known about previous over to the new randomized device, the people
+// owning previous own it too and a previous randomized MAC is retired into it
+func (s *Server) linkRandomized(ctx context.Context, device, previous *pb.Devices) error {
+	s.Logger.Info(fmt.Sprintf("Randomized MAC %s linked to %s", device.GetId().GetUUID(), previous.GetId().GetUUID()))
+	device.Name = previous.GetName()
+	if previous.GetRandomized() || randomizedMac(previous.GetId().GetMac()) {
+		return s.mergeDevices(ctx, device, previous)
+	}
+	device.Person = previous.GetPerson()
+	device.PresenceAware = previous.GetPresenceAware()
+	device.AwayTimeout = previous.GetAwayTimeout()
+	err := s.WriteNetworkDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+	people, err := s.Store.ListPeople(ctx)
+	if err != nil {
+		return err
+	}
+	for _, human := range people {
+		if !slices.Contains(human.GetIds(), previous.GetId().GetUUID()) || slices.Contains(human.GetIds(), device.GetId().GetUUID()) {
+			continue
+		}
+		human.Ids = append(human.Ids, device.GetId().GetUUID())
+		err = s.Store.PutPerson(ctx, human)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package house
+
+import (
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"testing"
+)
+
+func TestRandomizedMacRotation(t *testing.T) {
+	for mac, randomized := range map[string]bool{"DA:00:00:00:00:01": true, "A8:BB:CC:DD:EE:01": false, "03:00:00:00:00:01": false, "aus/10_0_0_1": false} {
+		if randomizedMac(mac) != randomized {
+			t.Errorf("randomizedMac(%s): expected %v", mac, randomized)
+		}
+	}
+	h := newTestHarness(t)
+	ctx := context.Background()
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.20", Mac: "DA:00:00:00:00:01", Hosts: []string{"pixel.lan"}})
+	if err := h.Store.PutPerson(ctx, &pb.People{Name: "sam", Ids: []string{"DA:00:00:00:00:01"}}); err != nil {
+		t.Fatal(err)
+	}
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.21", Mac: "DA:00:00:00:00:02", Hosts: []string{"pixel.lan"}})
+	if h.notifier.contains("New Device") {
+		t.Errorf("expected no new device notification for randomized MACs, got %v", h.notifier.sent)
+	}
+	phone, err := h.GetDevice("DA:00:00:00:00:02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !phone.GetRandomized() || phone.GetName() != "pixel.lan" {
+		t.Errorf("expected a randomized device keeping the name, got %v", phone)
+	}
+	if device, err := h.Store.GetDevice(ctx, "DA:00:00:00:00:01"); err != nil || device != nil {
+		t.Errorf("expected the previous randomized MAC to be retired, got %v %v", device, err)
+	}
+	sam, err := h.Store.GetPerson(ctx, "sam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sam.GetIds()) != 1 || sam.GetIds()[0] != "DA:00:00:00:00:02" {
+		t.Errorf("expected sam to own the new MAC, got %v", sam)
+	}
+}
@@ -0,0 +1,104 @@
+package house
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+var recordFormat = flag.String("recordFormat", "protobuf", "Encoding of stored records: protobuf, or yaml while servers that only read yaml share the store")
+
+// protobufRecord starts the records encoded as protobuf and is followed by recordVersion,
+// no YAML document starts with a NUL so both formats can be told apart while migrating
+var protobufRecord = []byte{0, 'p', 'b'}
+
+// recordVersion is the version of the protobuf record encoding
+const recordVersion = 1
+
+// checkRecordFormat fails for a -recordFormat marshalRecord can't write
+func checkRecordFormat() error {
+	switch *recordFormat {
+	case "yaml", "protobuf":
+		return nil
+	}
+	return fmt.Errorf("unknown record format: %s", *recordFormat)
+}
+
+// marshalRecord encodes item in the -recordFormat
+func marshalRecord(item proto.Message) ([]byte, error) {
+	switch *recordFormat {
+	case "yaml":
+		return yaml.Marshal(item)
+	case "protobuf":
+		data, err := proto.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		record := append([]byte{}, protobufRecord...)
+		record = append(record, recordVersion)
+		return append(record, data...), nil
+	}
+	return nil, fmt.Errorf("unknown record format: %s", *recordFormat)
+}
+
+// unmarshalRecord decodes a protobuf or YAML record into item
+func unmarshalRecord(data []byte, item proto.Message) error {
+	if !bytes.HasPrefix(data, protobufRecord) {
+		return yaml.Unmarshal(data, item)
+	}
+	data = data[len(protobufRecord):]
+	if len(data) == 0 || data[0] != recordVersion {
+		return fmt.Errorf("unsupported record version")
+	}
+	return proto.Unmarshal(data[1:], item)
+}
+
+// MigrateRecords rewrites every device, ble device, timed command and person in the
+// -recordFormat and returns how many records were written
+func MigrateRecords(ctx context.Context, store Store) (int, error) {
+	count := 0
+	devices, err := store.ListDevices(ctx)
+	if err != nil {
+		return count, err
+	}
+	for _, item := range devices {
+		if err := store.PutDevice(ctx, item); err != nil {
+			return count, err
+		}
+		count++
+	}
+	bles, err := store.ListBleDevices(ctx)
+	if err != nil {
+		return count, err
+	}
+	for _, item := range bles {
+		if err := store.PutBleDevice(ctx, item); err != nil {
+			return count, err
+		}
+		count++
+	}
+	tcs, err := store.ListTimedCommands(ctx, "")
+	if err != nil {
+		return count, err
+	}
+	for _, item := range tcs {
+		if err := store.PutTimedCommand(ctx, item); err != nil {
+			return count, err
+		}
+		count++
+	}
+	people, err := store.ListPeople(ctx)
+	if err != nil {
+		return count, err
+	}
+	for _, item := range people {
+		if err := store.PutPerson(ctx, item); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
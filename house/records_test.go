@@ -0,0 +1,45 @@
+package house
+
+import (
+	"bytes"
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"gopkg.in/yaml.v2"
+	"testing"
+)
+
+func TestMigrateRecords(t *testing.T) {
+	h := newTestHarness(t)
+	ctx := context.Background()
+	legacy, err := yaml.Marshal(&pb.Devices{Id: &pb.NetworkId{Mac: "AA:BB:CC:DD:EE:10", UUID: "AA:BB:CC:DD:EE:10"}, Home: "aus", AwayTimeout: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.mem.Put(ctx, devicesPrefix+"AA:BB:CC:DD:EE:10", string(legacy)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Store.PutPerson(ctx, &pb.People{Name: "sam", Ids: []string{"AA:BB:CC:DD:EE:10"}}); err != nil {
+		t.Fatal(err)
+	}
+	count, err := MigrateRecords(ctx, h.Store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 records to be migrated, got %d", count)
+	}
+	raw, err := h.mem.Get(ctx, devicesPrefix+"AA:BB:CC:DD:EE:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(raw.Kvs[0].Value, protobufRecord) {
+		t.Errorf("expected the device to be rewritten as protobuf, got %q", raw.Kvs[0].Value)
+	}
+	device, err := h.Store.GetDevice(ctx, "AA:BB:CC:DD:EE:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if device.GetHome() != "aus" || device.GetAwayTimeout() != 90 {
+		t.Errorf("unexpected device after migration %v", device)
+	}
+}
@@ -0,0 +1,593 @@
+package house
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// apiPrefix is where the REST API is served, ids containing a / must be sent escaped as %2F
+const apiPrefix = "/api/v1/"
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+// errMalformed marks request bodies that could not be read into their message
+var errMalformed = errors.New("malformed request body")
+
+// API endpoint serving the /api/v1 REST API, the JSON bodies are the protojson form of the gRPC messages
+func (s *Server) API(w http.ResponseWriter, req *http.Request) {
+	segments, err := apiSegments(req)
+	if err != nil {
+		apiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	switch segments[0] {
+	case "openapi.yaml":
+		if !allowMethods(w, req, http.MethodGet) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(http.StatusOK)
+		w.Write(openAPI)
+	case "devices":
+		s.apiDevices(w, req, segments[1:])
+	case "ble":
+		s.apiBle(w, req, segments[1:])
+	case "commands":
+		s.apiCommands(w, req, segments[1:])
+	case "people":
+		s.apiPeople(w, req, segments[1:])
+	case "homes":
+		s.apiHomes(w, req, segments[1:])
+	default:
+		apiError(w, http.StatusNotFound, fmt.Sprintf("unknown resource: %s", segments[0]))
+	}
+}
+
+// apiSegments splits the path after apiPrefix, each segment is unescaped on its own so ids may hold a /
+func apiSegments(req *http.Request) ([]string, error) {
+	path := strings.Trim(strings.TrimPrefix(req.URL.EscapedPath(), apiPrefix), "/")
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, err
+		}
+		segments[i] = unescaped
+	}
+	return segments, nil
+}
+
+// apiError replies with status and a JSON error message
+func apiError(w http.ResponseWriter, status int, message string) {
+	js, _ := json.Marshal(map[string]string{"error": message})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(js)
+}
+
+// apiStoreError replies 500 with err and logs it
+func (s *Server) apiStoreError(w http.ResponseWriter, err error) {
+	s.Logger.Error(err.Error())
+	apiError(w, http.StatusInternalServerError, err.Error())
+}
+
+// writeMessage replies with status and msg as protojson
+func writeMessage(w http.ResponseWriter, status int, msg proto.Message) {
+	js, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+	if err != nil {
+		apiError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(js)
+}
+
+// readMessage reads the protojson request body into msg
+func readMessage(req *http.Request, msg proto.Message) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	err = protojson.Unmarshal(body, msg)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errMalformed, err)
+	}
+	return nil
+}
+
+// patchMessage sets the fields present in the protojson request body on dst, the fields
+// left out keep their value and an explicit null clears one
+func patchMessage(req *http.Request, dst proto.Message) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	present := make(map[string]json.RawMessage)
+	err = json.Unmarshal(body, &present)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errMalformed, err)
+	}
+	patch := dst.ProtoReflect().New()
+	err = protojson.Unmarshal(body, patch.Interface())
+	if err != nil {
+		return fmt.Errorf("%w: %v", errMalformed, err)
+	}
+	target := dst.ProtoReflect()
+	fields := target.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		_, byJSONName := present[field.JSONName()]
+		_, byName := present[string(field.Name())]
+		if !byJSONName && !byName {
+			continue
+		}
+		if patch.Has(field) {
+			target.Set(field, patch.Get(field))
+		} else {
+			target.Clear(field)
+		}
+	}
+	return nil
+}
+
+// allowMethods replies 405 unless req uses one of methods
+func allowMethods(w http.ResponseWriter, req *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if req.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	apiError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", req.Method))
+	return false
+}
+
+// readError replies 400 for a malformed body and 500 otherwise
+func (s *Server) readError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errMalformed) {
+		apiError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.apiStoreError(w, err)
+}
+
+// apiDevices serves /devices, /devices/{uuid} and /devices/{uuid}/merge
+func (s *Server) apiDevices(w http.ResponseWriter, req *http.Request, segments []string) {
+	ctx := req.Context()
+	if len(segments) == 0 || segments[0] == "" {
+		if !allowMethods(w, req, http.MethodGet) {
+			return
+		}
+		request, err := devicesQuery(req.URL.Query())
+		if err != nil {
+			apiError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		devices, err := s.listDevices(ctx, request)
+		if status.Code(err) == codes.InvalidArgument {
+			apiError(w, http.StatusBadRequest, status.Convert(err).Message())
+			return
+		}
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		writeMessage(w, http.StatusOK, devices)
+		return
+	}
+	id := segments[0]
+	merge := len(segments) == 2 && segments[1] == "merge"
+	if len(segments) > 2 || (len(segments) == 2 && !merge) {
+		apiError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if merge {
+		s.apiMergeDevices(w, req, id)
+		return
+	}
+	if !allowMethods(w, req, http.MethodGet, http.MethodPatch, http.MethodDelete) {
+		return
+	}
+	device, err := s.Store.GetDevice(ctx, id)
+	if err != nil {
+		s.apiStoreError(w, err)
+		return
+	}
+	if device == nil {
+		apiError(w, http.StatusNotFound, fmt.Sprintf("unknown device: %s", id))
+		return
+	}
+	switch req.Method {
+	case http.MethodGet:
+		writeMessage(w, http.StatusOK, device)
+	case http.MethodPatch:
+		person := device.GetPerson()
+		err = patchMessage(req, device)
+		if err != nil {
+			s.readError(w, err)
+			return
+		}
+		if device.GetId().GetUUID() != id {
+			apiError(w, http.StatusBadRequest, "Id.UUID can not be changed")
+			return
+		}
+		if device.GetAwayTimeout() < 0 {
+			apiError(w, http.StatusBadRequest, "AwayTimeout must not be negative")
+			return
+		}
+		// the alive value of the device says whether it is a person
+		update := s.UpdateDevice
+		if device.GetPerson() != person {
+			update = s.TogglePerson
+		}
+		_, err = update(ctx, device)
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		writeMessage(w, http.StatusOK, device)
+	case http.MethodDelete:
+		_, err = s.DeleteDevice(ctx, &pb.StringRequest{Key: id})
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// apiMergeDevices serves /devices/{uuid}/merge, the devices in the body are folded into id
+func (s *Server) apiMergeDevices(w http.ResponseWriter, req *http.Request, id string) {
+	if !allowMethods(w, req, http.MethodPost) {
+		return
+	}
+	request := &pb.MergeDevicesRequest{}
+	err := readMessage(req, request)
+	if err != nil {
+		s.readError(w, err)
+		return
+	}
+	request.Target = id
+	device, err := s.mergeDeviceIds(req.Context(), request)
+	switch status.Code(err) {
+	case codes.OK:
+		writeMessage(w, http.StatusOK, device)
+	case codes.InvalidArgument:
+		apiError(w, http.StatusBadRequest, status.Convert(err).Message())
+	case codes.NotFound:
+		apiError(w, http.StatusNotFound, status.Convert(err).Message())
+	default:
+		s.apiStoreError(w, err)
+	}
+}
+
+// apiBle serves /ble and /ble/{mac}
+func (s *Server) apiBle(w http.ResponseWriter, req *http.Request, segments []string) {
+	ctx := req.Context()
+	if len(segments) == 0 || segments[0] == "" {
+		if !allowMethods(w, req, http.MethodGet) {
+			return
+		}
+		bles, err := s.readBleConfigAsSlice()
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		writeMessage(w, http.StatusOK, &pb.TCsResponse{Bles: bles})
+		return
+	}
+	if len(segments) > 1 {
+		apiError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if !allowMethods(w, req, http.MethodGet, http.MethodPatch) {
+		return
+	}
+	id := segments[0]
+	ble, err := s.Store.GetBleDevice(ctx, id)
+	if err != nil {
+		s.apiStoreError(w, err)
+		return
+	}
+	if ble == nil {
+		apiError(w, http.StatusNotFound, fmt.Sprintf("unknown ble device: %s", id))
+		return
+	}
+	if req.Method == http.MethodPatch {
+		err = patchMessage(req, ble)
+		if err != nil {
+			s.readError(w, err)
+			return
+		}
+		if ble.GetId() != id {
+			apiError(w, http.StatusBadRequest, "Id can not be changed")
+			return
+		}
+		if ble.GetAwayTimeout() < 0 {
+			apiError(w, http.StatusBadRequest, "AwayTimeout must not be negative")
+			return
+		}
+		err = s.Store.PutBleDevice(ctx, ble)
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+	}
+	writeMessage(w, http.StatusOK, ble)
+}
+
+// apiCommands serves /commands, /commands/{id} and /commands/{id}/complete
+func (s *Server) apiCommands(w http.ResponseWriter, req *http.Request, segments []string) {
+	ctx := req.Context()
+	if len(segments) == 0 || segments[0] == "" {
+		if !allowMethods(w, req, http.MethodGet, http.MethodPost) {
+			return
+		}
+		if req.Method == http.MethodGet {
+			cqs, err := s.ListCommandQueue(ctx, nil)
+			if err != nil {
+				s.apiStoreError(w, err)
+				return
+			}
+			sort.Sort(ByExecutedAt{cqs.Cqs})
+			writeMessage(w, http.StatusOK, cqs)
+			return
+		}
+		command := &pb.TimedCommands{}
+		err := readMessage(req, command)
+		if err != nil {
+			s.readError(w, err)
+			return
+		}
+		if command.GetId() == "" || command.GetCommand() == "" {
+			apiError(w, http.StatusBadRequest, "id and command are required")
+			return
+		}
+		existing, err := s.Store.ListTimedCommands(ctx, command.GetId())
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		if _, ok := existing[command.GetId()]; ok {
+			apiError(w, http.StatusConflict, fmt.Sprintf("command %s already exists", command.GetId()))
+			return
+		}
+		if command.GetExecuteat() == 0 {
+			command.Executeat = time.Now().Unix()
+		}
+		err = s.storeTimedCommand(command)
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		writeMessage(w, http.StatusCreated, command)
+		return
+	}
+	id := segments[0]
+	complete := len(segments) == 2 && segments[1] == "complete"
+	if len(segments) > 2 || (len(segments) == 2 && !complete) {
+		apiError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if complete && !allowMethods(w, req, http.MethodPost) {
+		return
+	}
+	if !complete && !allowMethods(w, req, http.MethodGet, http.MethodDelete) {
+		return
+	}
+	// ListTimedCommands matches on prefix, only the exact id is the command
+	items, err := s.Store.ListTimedCommands(ctx, id)
+	if err != nil {
+		s.apiStoreError(w, err)
+		return
+	}
+	command, ok := items[id]
+	if !ok {
+		apiError(w, http.StatusNotFound, fmt.Sprintf("unknown command: %s", id))
+		return
+	}
+	switch {
+	case complete:
+		command.Executeat = time.Now().Unix()
+		err = s.writeTc(command)
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		writeMessage(w, http.StatusOK, command)
+	case req.Method == http.MethodGet:
+		writeMessage(w, http.StatusOK, command)
+	default:
+		err = s.Store.DeleteTimedCommand(ctx, id)
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// apiPeople serves /people, /people/{name} and /people/{name}/devices
+func (s *Server) apiPeople(w http.ResponseWriter, req *http.Request, segments []string) {
+	ctx := req.Context()
+	if len(segments) == 0 || segments[0] == "" {
+		if !allowMethods(w, req, http.MethodGet, http.MethodPost) {
+			return
+		}
+		if req.Method == http.MethodGet {
+			people, err := s.ListPeopleRequest(ctx)
+			if err != nil {
+				s.apiStoreError(w, err)
+				return
+			}
+			writeMessage(w, http.StatusOK, people)
+			return
+		}
+		person := &pb.People{}
+		err := readMessage(req, person)
+		if err != nil {
+			s.readError(w, err)
+			return
+		}
+		if person.GetName() == "" {
+			apiError(w, http.StatusBadRequest, "Name is required")
+			return
+		}
+		existing, err := s.Store.GetPerson(ctx, person.GetName())
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		if existing != nil {
+			apiError(w, http.StatusConflict, fmt.Sprintf("person %s already exists", person.GetName()))
+			return
+		}
+		s.apiPutPerson(w, req, person, http.StatusCreated)
+		return
+	}
+	name := segments[0]
+	devices := len(segments) == 2 && segments[1] == "devices"
+	if len(segments) > 2 || (len(segments) == 2 && !devices) {
+		apiError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if devices && !allowMethods(w, req, http.MethodPost) {
+		return
+	}
+	if !devices && !allowMethods(w, req, http.MethodGet, http.MethodPut, http.MethodDelete) {
+		return
+	}
+	existing, err := s.Store.GetPerson(ctx, name)
+	if err != nil {
+		s.apiStoreError(w, err)
+		return
+	}
+	if existing == nil {
+		apiError(w, http.StatusNotFound, fmt.Sprintf("unknown person: %s", name))
+		return
+	}
+	switch {
+	case devices:
+		request := &pb.AssignDeviceRequest{}
+		err = readMessage(req, request)
+		if err != nil {
+			s.readError(w, err)
+			return
+		}
+		if request.GetId() == "" {
+			apiError(w, http.StatusBadRequest, "id is required")
+			return
+		}
+		if !slices.Contains(existing.GetIds(), request.GetId()) {
+			existing.Ids = append(existing.Ids, request.GetId())
+		}
+		s.apiPutPerson(w, req, existing, http.StatusOK)
+	case req.Method == http.MethodGet:
+		people, err := s.presence(ctx)
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		writeMessage(w, http.StatusOK, people[name])
+	case req.Method == http.MethodPut:
+		person := &pb.People{}
+		err = readMessage(req, person)
+		if err != nil {
+			s.readError(w, err)
+			return
+		}
+		if person.GetName() != "" && person.GetName() != name {
+			apiError(w, http.StatusBadRequest, "Name can not be changed")
+			return
+		}
+		person.Name = name
+		s.apiPutPerson(w, req, person, http.StatusOK)
+	default:
+		_, err = s.DeletePerson(ctx, &pb.StringRequest{Key: name})
+		if err != nil {
+			s.apiStoreError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// apiPutPerson stores person and replies with code and their presence, 400 when they own an unknown device
+func (s *Server) apiPutPerson(w http.ResponseWriter, req *http.Request, person *pb.People, code int) {
+	_, err := s.putPerson(req.Context(), person)
+	if status.Code(err) == codes.NotFound {
+		apiError(w, http.StatusBadRequest, status.Convert(err).Message())
+		return
+	}
+	if err != nil {
+		s.apiStoreError(w, err)
+		return
+	}
+	people, err := s.presence(req.Context())
+	if err != nil {
+		s.apiStoreError(w, err)
+		return
+	}
+	writeMessage(w, code, people[person.GetName()])
+}
+
+// homes returns every home with its empty state and the people in it, sorted by name
+func (s *Server) homes(req *http.Request) ([]*pb.Home, error) {
+	items, err := s.Store.ListHomes(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	homes := make([]*pb.Home, 0, len(items))
+	for name, empty := range items {
+		people, err := s.GetPeopleInHouses(req.Context(), name)
+		if err != nil {
+			return nil, err
+		}
+		homes = append(homes, &pb.Home{Name: name, Empty: empty, People: people})
+	}
+	sort.Slice(homes, func(i, j int) bool { return homes[i].GetName() < homes[j].GetName() })
+	return homes, nil
+}
+
+// apiHomes serves /homes and /homes/{name}
+func (s *Server) apiHomes(w http.ResponseWriter, req *http.Request, segments []string) {
+	if len(segments) > 1 {
+		apiError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if !allowMethods(w, req, http.MethodGet) {
+		return
+	}
+	homes, err := s.homes(req)
+	if err != nil {
+		s.apiStoreError(w, err)
+		return
+	}
+	if len(segments) == 0 || segments[0] == "" {
+		writeMessage(w, http.StatusOK, &pb.HomesResponse{Homes: homes})
+		return
+	}
+	for _, home := range homes {
+		if home.GetName() == segments[0] {
+			writeMessage(w, http.StatusOK, home)
+			return
+		}
+	}
+	apiError(w, http.StatusNotFound, fmt.Sprintf("unknown home: %s", segments[0]))
+}
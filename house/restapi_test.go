@@ -0,0 +1,153 @@
+package house
+
+import (
+	"context"
+	"encoding/json"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRestAPI(t *testing.T) {
+	h := newTestHarness(t)
+	keys, err := NewKeyStore([]*APIKey{
+		{Name: "lounge", Key: "agent-key", Scopes: []string{ScopeAgentReport}},
+		{Name: "ops", Key: "admin-key", Scopes: []string{ScopeAdmin}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(RequireAPIKey(keys, http.HandlerFunc(h.API)))
+	defer srv.Close()
+	phone := &pb.Devices{
+		Id:   &pb.NetworkId{Ip: "192.168.1.12", Mac: "AA:BB:CC:DD:EE:08", UUID: "AA:BB:CC:DD:EE:08"},
+		Home: "aus",
+		Name: "phone",
+	}
+	if _, err := h.UpdateDevice(context.Background(), phone); err != nil {
+		t.Fatal(err)
+	}
+
+	call := func(method, path, key, body string) (int, []byte) {
+		req, err := http.NewRequest(method, srv.URL+apiPrefix+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != "" {
+			req.Header.Set("apikey", key)
+		}
+		res, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		data, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode, data
+	}
+
+	tests := []struct {
+		name, method, path, key, body string
+		status                        int
+	}{
+		{"unknown device", "GET", "devices/AA:BB:CC:DD:EE:09", "", "", http.StatusNotFound},
+		{"patch without key", "PATCH", "devices/AA:BB:CC:DD:EE:08", "", `{"Person": true}`, http.StatusUnauthorized},
+		{"patch with agent key", "PATCH", "devices/AA:BB:CC:DD:EE:08", "agent-key", `{"Person": true}`, http.StatusForbidden},
+		{"patch uuid", "PATCH", "devices/AA:BB:CC:DD:EE:08", "admin-key", `{"Id": {"UUID": "other"}}`, http.StatusBadRequest},
+		{"patch device", "PATCH", "devices/AA:BB:CC:DD:EE:08", "admin-key", `{"Person": true, "AwayTimeout": "60"}`, http.StatusOK},
+		{"create person", "POST", "people", "admin-key", `{"Name": "sam"}`, http.StatusCreated},
+		{"create person twice", "POST", "people", "admin-key", `{"Name": "sam"}`, http.StatusConflict},
+		{"assign unknown device", "POST", "people/sam/devices", "admin-key", `{"id": "AA:BB:CC:DD:EE:09"}`, http.StatusBadRequest},
+		{"assign device", "POST", "people/sam/devices", "admin-key", `{"id": "AA:BB:CC:DD:EE:08"}`, http.StatusOK},
+		{"malformed command", "POST", "commands", "admin-key", `{"id": 1}`, http.StatusBadRequest},
+		{"create command", "POST", "commands", "admin-key", `{"id": "lights", "command": "lights off", "executeat": "4102444800"}`, http.StatusCreated},
+		{"complete command", "POST", "commands/lights/complete", "admin-key", "", http.StatusOK},
+		{"delete command", "DELETE", "commands/lights", "admin-key", "", http.StatusNoContent},
+		{"delete command twice", "DELETE", "commands/lights", "admin-key", "", http.StatusNotFound},
+		{"replace devices", "PUT", "devices", "admin-key", "", http.StatusMethodNotAllowed},
+		{"openapi", "GET", "openapi.yaml", "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		status, data := call(tt.method, tt.path, tt.key, tt.body)
+		if status != tt.status {
+			t.Errorf("%s: expected %d, got %d %s", tt.name, tt.status, status, data)
+		}
+	}
+
+	_, data := call("GET", "devices/AA:BB:CC:DD:EE:08", "", "")
+	device := &pb.Devices{}
+	if err := protojson.Unmarshal(data, device); err != nil {
+		t.Fatal(err)
+	}
+	if !device.GetPerson() || device.GetAwayTimeout() != 60 || device.GetName() != "phone" {
+		t.Errorf("expected patch to keep the fields left out, got %v", device)
+	}
+	if alive, err := h.Store.ListAlive(context.Background(), "aus"); err != nil || alive["aus/AA:BB:CC:DD:EE:08"] != "person" {
+		t.Errorf("expected patching Person to make the phone alive as a person, got %v %v", alive, err)
+	}
+	_, data = call("GET", "people/sam", "", "")
+	person := &pb.People{}
+	if err := protojson.Unmarshal(data, person); err != nil {
+		t.Fatal(err)
+	}
+	if len(person.GetIds()) != 1 || person.GetIds()[0] != "AA:BB:CC:DD:EE:08" {
+		t.Errorf("expected sam to own the phone, got %v", person)
+	}
+	status, data := call("GET", "people/alex", "", "")
+	body := map[string]string{}
+	if err := json.Unmarshal(data, &body); err != nil || status != http.StatusNotFound || body["error"] == "" {
+		t.Errorf("expected a JSON 404 error, got %d %s", status, data)
+	}
+}
+
+func TestRestAPIDeleteDevice(t *testing.T) {
+	h := newTestHarness(t)
+	srv := httptest.NewServer(http.HandlerFunc(h.API))
+	defer srv.Close()
+	ctx := context.Background()
+	h.report(t, "aus", &pb.AddressRequest{Ip: "10.0.0.5", Mac: "AA:BB:CC:DD:EE:05"})
+	eventually(t, func() bool { return h.hasGauge("AA:BB:CC:DD:EE:05") }, "expected the device to be exported")
+	req, err := http.NewRequest("DELETE", srv.URL+apiPrefix+"devices/AA:BB:CC:DD:EE:05", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected the device to be deleted, got %d", res.StatusCode)
+	}
+	if alive, err := h.Store.ListAlive(ctx, "aus"); err != nil || len(alive) != 0 {
+		t.Errorf("expected the alive key to be deleted, got %v %v", alive, err)
+	}
+	if h.hasGauge("AA:BB:CC:DD:EE:05") {
+		t.Error("expected the gauge of the deleted device to be forgotten")
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	srv := httptest.NewServer(http.StripPrefix("/dashboard/", Dashboard()))
+	defer srv.Close()
+	for path, content := range map[string]string{"": "dashboard.js", "dashboard.js": apiPrefix[1:]} {
+		res, err := srv.Client().Get(srv.URL + "/dashboard/" + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != http.StatusOK || !strings.Contains(string(data), content) {
+			t.Errorf("/dashboard/%s: expected %s, got %d", path, content, res.StatusCode)
+		}
+	}
+}
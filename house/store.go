@@ -0,0 +1,151 @@
+package house
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/beaujr/nmap_prometheus/etcd"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"path"
+	"strings"
+)
+
+var (
+	storeType = flag.String("store", "etcd", "Storage backend to use: etcd or bolt")
+	boltPath  = flag.String("boltPath", "config/nmap_prometheus.db", "Path to the bolt database when -store=bolt")
+)
+
+// Store persists devices, people, homes, timed commands and presence leases
+type Store interface {
+	// GetDevice returns the device with the UUID or nil if it is unknown
+	GetDevice(ctx context.Context, id string) (*pb.Devices, error)
+	// ListDevices returns all devices keyed by UUID
+	ListDevices(ctx context.Context) (map[string]*pb.Devices, error)
+	// ScanDevices returns up to limit devices and their keys ordered by key starting after the key
+	// after, more reports whether devices are left past the last one returned
+	ScanDevices(ctx context.Context, after string, limit int64) (keys []string, items []*pb.Devices, more bool, err error)
+	// PutDevice stores item and indexes a device known by MAC under its ip and hostnames
+	PutDevice(ctx context.Context, item *pb.Devices) error
+	DeleteDevice(ctx context.Context, id string) error
+	// FindDevices returns the devices indexed under ip or one of the hostnames in home. The index
+	// is only a hint as devices move and are deleted so callers check the devices still match
+	FindDevices(ctx context.Context, home, ip string, hostnames []string) ([]*pb.Devices, error)
+
+	// GetBleDevice returns the ble device with the id or nil if it is unknown
+	GetBleDevice(ctx context.Context, id string) (*pb.BleDevices, error)
+	// ListBleDevices returns all ble devices keyed by id
+	ListBleDevices(ctx context.Context) (map[string]*pb.BleDevices, error)
+	PutBleDevice(ctx context.Context, item *pb.BleDevices) error
+	DeleteBleDevice(ctx context.Context, id string) error
+
+	// ListTimedCommands returns all timed commands whose id starts with prefix keyed by id
+	ListTimedCommands(ctx context.Context, prefix string) (map[string]*pb.TimedCommands, error)
+	PutTimedCommand(ctx context.Context, item *pb.TimedCommands) error
+	DeleteTimedCommand(ctx context.Context, id string) error
+	// DeleteTimedCommands deletes all timed commands whose id starts with prefix
+	DeleteTimedCommands(ctx context.Context, prefix string) error
+
+	// GetPerson returns the person with the name or nil if they are unknown
+	GetPerson(ctx context.Context, name string) (*pb.People, error)
+	// ListPeople returns all people keyed by name
+	ListPeople(ctx context.Context) (map[string]*pb.People, error)
+	PutPerson(ctx context.Context, person *pb.People) error
+	DeletePerson(ctx context.Context, name string) error
+
+	// ListHomes returns the empty state of every home
+	ListHomes(ctx context.Context) (map[string]bool, error)
+	PutHome(ctx context.Context, home string, empty bool) error
+	DeleteHome(ctx context.Context, home string) error
+
+	// GrantLease marks the mac alive in home for ttl seconds, refreshing an existing lease
+	GrantLease(ctx context.Context, home, mac, value string, ttl int64) error
+	// PutAlive marks the mac alive in home without expiry
+	PutAlive(ctx context.Context, home, mac, value string) error
+	// DeleteAlive removes the alive key of mac in home, watchers see it as expired
+	DeleteAlive(ctx context.Context, home, mac string) error
+	// ListAlive returns the value of every alive key in home, or every home when it is empty, keyed by <home>/<mac>
+	ListAlive(ctx context.Context, home string) (map[string]string, error)
+	// WatchAlive streams alive keys being created and expiring until ctx is done
+	WatchAlive(ctx context.Context) (<-chan AliveEvent, error)
+
+	// GetVendor returns the cached vendor of the OUI, found is false once it expired and
+	// a blank vendor caches an OUI the lookup did not know
+	GetVendor(ctx context.Context, oui string) (vendor string, found bool, err error)
+	// PutVendor caches the vendor of the OUI for ttl seconds
+	PutVendor(ctx context.Context, oui, vendor string, ttl int64) error
+
+	GetLastNotification(ctx context.Context) (*string, error)
+	PutLastNotification(ctx context.Context, notification string) error
+}
+
+// ipIndexKey and hostIndexKey are the index keys FindDevices looks devices up by
+func ipIndexKey(home, ip string) string {
+	return path.Join(home, "ip", ip)
+}
+
+func hostIndexKey(home, hostname string) string {
+	return path.Join(home, "host", strings.ToLower(hostname))
+}
+
+// indexKeys returns the index keys of item, only devices known by MAC are indexed
+func indexKeys(item *pb.Devices) []string {
+	if !hasMac(item) {
+		return nil
+	}
+	keys := make([]string, 0, len(item.GetHostnames())+1)
+	if item.GetId().GetIp() != "" {
+		keys = append(keys, ipIndexKey(item.GetHome(), item.GetId().GetIp()))
+	}
+	for _, host := range item.GetHostnames() {
+		keys = append(keys, hostIndexKey(item.GetHome(), host))
+	}
+	return keys
+}
+
+// findKeys returns the index keys FindDevices reads
+func findKeys(home, ip string, hostnames []string) []string {
+	keys := []string{ipIndexKey(home, ip)}
+	for _, host := range hostnames {
+		keys = append(keys, hostIndexKey(home, host))
+	}
+	return keys
+}
+
+// AliveEvent is an alive key being created or removed (expired, revoked or deleted)
+type AliveEvent struct {
+	Home    string
+	Mac     string
+	Value   string
+	Deleted bool
+}
+
+// alivePrefix is the prefix of the alive keys of home relative to AlivePrefix, the separator
+// keeps a home from matching the homes it is a prefix of
+func alivePrefix(home string) string {
+	if home == "" {
+		return ""
+	}
+	return home + "/"
+}
+
+// aliveEvent splits an alive key relative to AlivePrefix into its home and mac,
+// macs synthesized from an ip are <home>/<ip> so only the first segment is the home
+func aliveEvent(key, value string, deleted bool) AliveEvent {
+	home, mac, _ := strings.Cut(strings.TrimPrefix(key, "/"), "/")
+	return AliveEvent{Home: home, Mac: mac, Value: value, Deleted: deleted}
+}
+
+// NewStore returns the Store selected by the -store flag, an unknown -recordFormat fails here rather than on the first write
+func NewStore() (Store, error) {
+	if err := checkRecordFormat(); err != nil {
+		return nil, err
+	}
+	switch *storeType {
+	case "etcd":
+		client, kv := etcd.NewClient(strings.Split(*etcdServers, ","))
+		return NewEtcdStore(kv, NewEtcdLeaser(client.Lease, kv), client.Watcher), nil
+	case "bolt":
+		return NewBoltStore(*boltPath)
+	}
+	return nil, fmt.Errorf("unknown store: %s", *storeType)
+}
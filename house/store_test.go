@@ -0,0 +1,49 @@
+package house
+
+import (
+	"context"
+	"github.com/beaujr/nmap_prometheus/etcd"
+	"strings"
+	"testing"
+)
+
+// testStores returns an in-memory etcd store and a bolt store to run the same test against
+func testStores(t *testing.T) map[string]Store {
+	t.Helper()
+	bolt, err := NewBoltStore(t.TempDir() + "/devices.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem := etcd.NewMemory()
+	return map[string]Store{"etcd": NewEtcdStore(mem, NewEtcdLeaser(mem, mem), mem), "bolt": bolt}
+}
+
+func TestListAliveHome(t *testing.T) {
+	for name, store := range testStores(t) {
+		ctx := context.Background()
+		if err := store.PutAlive(ctx, "aus", "AA:BB:CC:DD:EE:01", "person"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.PutAlive(ctx, "aus2", "AA:BB:CC:DD:EE:02", "person"); err != nil {
+			t.Fatal(err)
+		}
+		alive, err := store.ListAlive(ctx, "aus")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(alive) != 1 || alive["aus/AA:BB:CC:DD:EE:01"] != "person" {
+			t.Errorf("%s: expected only the aus key, got %v", name, alive)
+		}
+		if alive, err = store.ListAlive(ctx, ""); err != nil || len(alive) != 2 {
+			t.Errorf("%s: expected every home, got %v %v", name, alive, err)
+		}
+	}
+}
+
+func TestUnknownRecordFormat(t *testing.T) {
+	defer func(format string) { *recordFormat = format }(*recordFormat)
+	*recordFormat = "json"
+	if _, err := NewStore(); err == nil || !strings.Contains(err.Error(), "json") {
+		t.Errorf("expected an unknown record format to fail, got %v", err)
+	}
+}
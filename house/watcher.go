@@ -0,0 +1,111 @@
+package house
+
+import (
+	"context"
+	"fmt"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+)
+
+// watchLeases follows the alive keys so devices are marked away and homes empty
+// as their leases expire rather than only when a device is reported again
+func watchLeases(server *Server) {
+	events, err := server.Store.WatchAlive(server.GetContext())
+	if err != nil {
+		server.Logger.Error(err.Error())
+		return
+	}
+	go func() {
+		for event := range events {
+			var err error
+			if event.Deleted {
+				err = server.deviceLeft(server.GetContext(), event.Home, event.Mac)
+			} else {
+				err = server.deviceArrived(server.GetContext(), event.Home, event.Mac)
+			}
+			if err != nil {
+				server.Logger.Error(err.Error())
+			}
+			err = server.registerPeopleMetrics(server.GetContext())
+			if err != nil {
+				server.Logger.Error(err.Error())
+			}
+		}
+	}()
+}
+
+// deviceLeft handles the alive key of mac expiring in home
+func (s *Server) deviceLeft(ctx context.Context, home, mac string) error {
+	device, err := s.Store.GetDevice(ctx, mac)
+	if err != nil {
+		return err
+	}
+	if device == nil {
+		s.forgetGauge(mac)
+	}
+	// the device may have been deleted or reported from another home since
+	if device != nil && device.GetHome() == home {
+		device.Away = true
+		err = s.WriteNetworkDevice(ctx, device)
+		if err != nil {
+			return err
+		}
+		s.RegisterMetric(device)
+		if device.GetPerson() {
+			err = s.NotificationClient.SendNotification(home, fmt.Sprintf("%s has left", device.GetName()), home)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	err = s.publishDeviceEvent(ctx, pb.PresenceEventType_DEVICE_LEFT, home, mac, device)
+	if err != nil {
+		return err
+	}
+	homes, err := s.Store.ListHomes(ctx)
+	if err != nil {
+		return err
+	}
+	if !homes[home] && s.IsHouseEmpty(ctx, home) {
+		return s.ToggleHouseStatus(home, true)
+	}
+	return nil
+}
+
+// deviceArrived handles a new alive key in home, the home is occupied again
+// once the device brings one of its people home
+func (s *Server) deviceArrived(ctx context.Context, home, mac string) error {
+	device, err := s.Store.GetDevice(ctx, mac)
+	if err != nil {
+		return err
+	}
+	// devices written by another process, eg an admin import, are exported from here
+	if device != nil {
+		s.RegisterMetric(device)
+	}
+	err = s.publishDeviceEvent(ctx, pb.PresenceEventType_DEVICE_ARRIVED, home, mac, device)
+	if err != nil {
+		return err
+	}
+	homes, err := s.Store.ListHomes(ctx)
+	if err != nil {
+		return err
+	}
+	if homes[home] && !s.IsHouseEmpty(ctx, home) {
+		return s.ToggleHouseStatus(home, false)
+	}
+	return nil
+}
+
+// publishDeviceEvent publishes an event for the network device or, when it is nil, the ble device with mac
+func (s *Server) publishDeviceEvent(ctx context.Context, eventType pb.PresenceEventType, home, mac string, device *pb.Devices) error {
+	if device != nil {
+		s.publishEvent(&pb.PresenceEvent{Type: eventType, Home: home, Device: device})
+		return nil
+	}
+	ble, err := s.Store.GetBleDevice(ctx, mac)
+	if err != nil || ble == nil {
+		return err
+	}
+	s.publishEvent(&pb.PresenceEvent{Type: eventType, Home: home, Ble: ble})
+	return nil
+}
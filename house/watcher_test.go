@@ -0,0 +1,101 @@
+package house
+
+import (
+	"context"
+	pb "github.com/beaujr/nmap_prometheus/proto"
+	"testing"
+	"time"
+)
+
+func TestLeaseExpiryEmptiesHouse(t *testing.T) {
+	h := newTestHarness(t)
+	person := &pb.Devices{
+		Id:     &pb.NetworkId{Ip: "192.168.1.7", Mac: "AA:BB:CC:DD:EE:03", UUID: "AA:BB:CC:DD:EE:03"},
+		Home:   "aus",
+		Person: true,
+	}
+	if err := h.WriteNetworkDevice(context.Background(), person); err != nil {
+		t.Fatal(err)
+	}
+	in := &pb.AddressRequest{Ip: "192.168.1.7", Mac: "AA:BB:CC:DD:EE:03"}
+	h.report(t, "aus", in)
+	if h.IsHouseEmpty(context.Background(), "aus") {
+		t.Fatal("expected house to be occupied")
+	}
+
+	// a report inside the timeout refreshes the lease
+	h.mem.Advance(time.Duration(*TimeAwaySeconds-1) * time.Second)
+	h.report(t, "aus", in)
+	h.mem.Advance(time.Duration(*TimeAwaySeconds-1) * time.Second)
+	if h.IsHouseEmpty(context.Background(), "aus") {
+		t.Fatal("expected refreshed lease to keep house occupied")
+	}
+
+	h.mem.Advance(2 * time.Second)
+	if !h.IsHouseEmpty(context.Background(), "aus") {
+		t.Error("expected house to be empty once the lease expired")
+	}
+}
+
+func TestLeaseWatcherMarksAwayAndEmpty(t *testing.T) {
+	h := newTestHarness(t)
+	ctx := context.Background()
+	phone := &pb.Devices{
+		Id:     &pb.NetworkId{Ip: "192.168.1.9", Mac: "AA:BB:CC:DD:EE:05", UUID: "AA:BB:CC:DD:EE:05"},
+		Home:   "aus",
+		Name:   "phone",
+		Person: true,
+	}
+	tv := &pb.Devices{
+		Id:            &pb.NetworkId{Ip: "192.168.1.10", Mac: "AA:BB:CC:DD:EE:06", UUID: "AA:BB:CC:DD:EE:06"},
+		Home:          "aus",
+		Name:          "TV",
+		PresenceAware: true,
+	}
+	for _, device := range []*pb.Devices{phone, tv} {
+		if err := h.WriteNetworkDevice(ctx, device); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.Store.PutHome(ctx, "aus", false); err != nil {
+		t.Fatal(err)
+	}
+	in := &pb.AddressRequest{Ip: "192.168.1.9", Mac: "AA:BB:CC:DD:EE:05"}
+	h.report(t, "aus", in)
+
+	// the lease expiring is still seen after the watch is cancelled
+	h.mem.CancelWatches()
+	time.Sleep(1500 * time.Millisecond)
+	h.mem.Advance(time.Duration(*TimeAwaySeconds+1) * time.Second)
+	eventually(t, func() bool {
+		homes, err := h.ReadHomesConfig()
+		return err == nil && homes["aus"] != nil && *homes["aus"]
+	}, "expected aus to be marked empty")
+	device, err := h.GetDevice("AA:BB:CC:DD:EE:05")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !device.GetAway() {
+		t.Error("expected phone to be marked away")
+	}
+	if !h.notifier.contains("phone has left") {
+		t.Errorf("expected left notification, got %v", h.notifier.sent)
+	}
+	tcs, err := h.getTc()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tcs) != 1 {
+		t.Errorf("expected the TV shutdown to be scheduled, got %v", tcs)
+	}
+
+	h.report(t, "aus", in)
+	eventually(t, func() bool {
+		homes, err := h.ReadHomesConfig()
+		return err == nil && homes["aus"] != nil && !*homes["aus"]
+	}, "expected aus to be occupied again")
+	eventually(t, func() bool {
+		tcs, err := h.getTc()
+		return err == nil && len(tcs) == 0
+	}, "expected the TV shutdown to be cancelled")
+}
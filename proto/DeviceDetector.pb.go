@@ -8,6 +8,7 @@ package proto
 
 import (
 	proto "github.com/golang/protobuf/proto"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
@@ -26,6 +27,70 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type PresenceEventType int32
+
+const (
+	PresenceEventType_UNKNOWN               PresenceEventType = 0
+	PresenceEventType_DEVICE_ARRIVED        PresenceEventType = 1
+	PresenceEventType_DEVICE_LEFT           PresenceEventType = 2
+	PresenceEventType_DEVICE_MOVED_HOME     PresenceEventType = 3
+	PresenceEventType_PERSON_ARRIVED        PresenceEventType = 4
+	PresenceEventType_PERSON_LEFT           PresenceEventType = 5
+	PresenceEventType_HOUSE_EMPTY_CHANGED   PresenceEventType = 6
+	PresenceEventType_NEW_DEVICE_DISCOVERED PresenceEventType = 7
+)
+
+// Enum value maps for PresenceEventType.
+var (
+	PresenceEventType_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "DEVICE_ARRIVED",
+		2: "DEVICE_LEFT",
+		3: "DEVICE_MOVED_HOME",
+		4: "PERSON_ARRIVED",
+		5: "PERSON_LEFT",
+		6: "HOUSE_EMPTY_CHANGED",
+		7: "NEW_DEVICE_DISCOVERED",
+	}
+	PresenceEventType_value = map[string]int32{
+		"UNKNOWN":               0,
+		"DEVICE_ARRIVED":        1,
+		"DEVICE_LEFT":           2,
+		"DEVICE_MOVED_HOME":     3,
+		"PERSON_ARRIVED":        4,
+		"PERSON_LEFT":           5,
+		"HOUSE_EMPTY_CHANGED":   6,
+		"NEW_DEVICE_DISCOVERED": 7,
+	}
+)
+
+func (x PresenceEventType) Enum() *PresenceEventType {
+	p := new(PresenceEventType)
+	*p = x
+	return p
+}
+
+func (x PresenceEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PresenceEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_DeviceDetector_proto_enumTypes[0].Descriptor()
+}
+
+func (PresenceEventType) Type() protoreflect.EnumType {
+	return &file_DeviceDetector_proto_enumTypes[0]
+}
+
+func (x PresenceEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PresenceEventType.Descriptor instead.
+func (PresenceEventType) EnumDescriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{0}
+}
+
 // The request message containing the user's name.
 type StringRequest struct {
 	state         protoimpl.MessageState
@@ -332,9 +397,10 @@ type MQTTBleRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Agent    *MQTTAgent  `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
-	Bles     *BleRequest `protobuf:"bytes,2,opt,name=bles,proto3" json:"bles,omitempty"`
-	Metadata []*Metadata `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	Agent     *MQTTAgent  `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	Bles      *BleRequest `protobuf:"bytes,2,opt,name=bles,proto3" json:"bles,omitempty"`
+	Metadata  []*Metadata `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	Timestamp int64       `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 }
 
 func (x *MQTTBleRequest) Reset() {
@@ -390,6 +456,13 @@ func (x *MQTTBleRequest) GetMetadata() []*Metadata {
 	return nil
 }
 
+func (x *MQTTBleRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
 type MQTTAgent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -675,16 +748,26 @@ func (x *TCsResponse) GetBles() []*BleDevices {
 	return nil
 }
 
-type DevicesResponse struct {
+// ListDevicesRequest filters and pages ListDevices, devices are ordered by UUID
+type ListDevicesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Devices []*Devices `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	Home   string `protobuf:"bytes,1,opt,name=home,proto3" json:"home,omitempty"`
+	Person *bool  `protobuf:"varint,2,opt,name=person,proto3,oneof" json:"person,omitempty"`
+	Away   *bool  `protobuf:"varint,3,opt,name=away,proto3,oneof" json:"away,omitempty"`
+	// type is the type metadata of the device eg: network
+	Type string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	// manufacturer matches the vendor case insensitively
+	Manufacturer string `protobuf:"bytes,5,opt,name=manufacturer,proto3" json:"manufacturer,omitempty"`
+	// page_size 0 returns every matching device
+	PageSize  int32  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 }
 
-func (x *DevicesResponse) Reset() {
-	*x = DevicesResponse{}
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_DeviceDetector_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -692,13 +775,13 @@ func (x *DevicesResponse) Reset() {
 	}
 }
 
-func (x *DevicesResponse) String() string {
+func (x *ListDevicesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DevicesResponse) ProtoMessage() {}
+func (*ListDevicesRequest) ProtoMessage() {}
 
-func (x *DevicesResponse) ProtoReflect() protoreflect.Message {
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_DeviceDetector_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -710,35 +793,72 @@ func (x *DevicesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DevicesResponse.ProtoReflect.Descriptor instead.
-func (*DevicesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
 	return file_DeviceDetector_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *DevicesResponse) GetDevices() []*Devices {
+func (x *ListDevicesRequest) GetHome() string {
 	if x != nil {
-		return x.Devices
+		return x.Home
 	}
-	return nil
+	return ""
 }
 
-type BleDevices struct {
+func (x *ListDevicesRequest) GetPerson() bool {
+	if x != nil && x.Person != nil {
+		return *x.Person
+	}
+	return false
+}
+
+func (x *ListDevicesRequest) GetAway() bool {
+	if x != nil && x.Away != nil {
+		return *x.Away
+	}
+	return false
+}
+
+func (x *ListDevicesRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListDevicesRequest) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *ListDevicesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDevicesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type DevicesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string      `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
-	LastSeen int64       `protobuf:"varint,2,opt,name=LastSeen,proto3" json:"LastSeen,omitempty"`
-	Commands []*Commands `protobuf:"bytes,3,rep,name=commands,proto3" json:"commands,omitempty"`
-	Name     string      `protobuf:"bytes,4,opt,name=Name,proto3" json:"Name,omitempty"`
-	Home     string      `protobuf:"bytes,5,opt,name=Home,proto3" json:"Home,omitempty"`
-	Tile     bool        `protobuf:"varint,6,opt,name=tile,proto3" json:"tile,omitempty"`
-	Distance float32     `protobuf:"fixed32,7,opt,name=distance,proto3" json:"distance,omitempty"`
-	Metadata []*Metadata `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	Devices []*Devices `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	// next_page_token is blank on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 }
 
-func (x *BleDevices) Reset() {
-	*x = BleDevices{}
+func (x *DevicesResponse) Reset() {
+	*x = DevicesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_DeviceDetector_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -746,13 +866,13 @@ func (x *BleDevices) Reset() {
 	}
 }
 
-func (x *BleDevices) String() string {
+func (x *DevicesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BleDevices) ProtoMessage() {}
+func (*DevicesResponse) ProtoMessage() {}
 
-func (x *BleDevices) ProtoReflect() protoreflect.Message {
+func (x *DevicesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_DeviceDetector_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -764,79 +884,38 @@ func (x *BleDevices) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BleDevices.ProtoReflect.Descriptor instead.
-func (*BleDevices) Descriptor() ([]byte, []int) {
+// Deprecated: Use DevicesResponse.ProtoReflect.Descriptor instead.
+func (*DevicesResponse) Descriptor() ([]byte, []int) {
 	return file_DeviceDetector_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *BleDevices) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *BleDevices) GetLastSeen() int64 {
-	if x != nil {
-		return x.LastSeen
-	}
-	return 0
-}
-
-func (x *BleDevices) GetCommands() []*Commands {
+func (x *DevicesResponse) GetDevices() []*Devices {
 	if x != nil {
-		return x.Commands
+		return x.Devices
 	}
 	return nil
 }
 
-func (x *BleDevices) GetName() string {
-	if x != nil {
-		return x.Name
-	}
-	return ""
-}
-
-func (x *BleDevices) GetHome() string {
+func (x *DevicesResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Home
+		return x.NextPageToken
 	}
 	return ""
 }
 
-func (x *BleDevices) GetTile() bool {
-	if x != nil {
-		return x.Tile
-	}
-	return false
-}
-
-func (x *BleDevices) GetDistance() float32 {
-	if x != nil {
-		return x.Distance
-	}
-	return 0
-}
-
-func (x *BleDevices) GetMetadata() []*Metadata {
-	if x != nil {
-		return x.Metadata
-	}
-	return nil
-}
-
-type Commands struct {
+type Home struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Timeout int64  `protobuf:"varint,1,opt,name=Timeout,proto3" json:"Timeout,omitempty"`
-	Command string `protobuf:"bytes,2,opt,name=Command,proto3" json:"Command,omitempty"`
-	Id      string `protobuf:"bytes,3,opt,name=Id,proto3" json:"Id,omitempty"`
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Empty bool   `protobuf:"varint,2,opt,name=empty,proto3" json:"empty,omitempty"`
+	// names of the people currently in the home
+	People []string `protobuf:"bytes,3,rep,name=people,proto3" json:"people,omitempty"`
 }
 
-func (x *Commands) Reset() {
-	*x = Commands{}
+func (x *Home) Reset() {
+	*x = Home{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_DeviceDetector_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -844,13 +923,13 @@ func (x *Commands) Reset() {
 	}
 }
 
-func (x *Commands) String() string {
+func (x *Home) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Commands) ProtoMessage() {}
+func (*Home) ProtoMessage() {}
 
-func (x *Commands) ProtoReflect() protoreflect.Message {
+func (x *Home) ProtoReflect() protoreflect.Message {
 	mi := &file_DeviceDetector_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -862,47 +941,44 @@ func (x *Commands) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Commands.ProtoReflect.Descriptor instead.
-func (*Commands) Descriptor() ([]byte, []int) {
+// Deprecated: Use Home.ProtoReflect.Descriptor instead.
+func (*Home) Descriptor() ([]byte, []int) {
 	return file_DeviceDetector_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *Commands) GetTimeout() int64 {
+func (x *Home) GetName() string {
 	if x != nil {
-		return x.Timeout
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *Commands) GetCommand() string {
+func (x *Home) GetEmpty() bool {
 	if x != nil {
-		return x.Command
+		return x.Empty
 	}
-	return ""
+	return false
 }
 
-func (x *Commands) GetId() string {
+func (x *Home) GetPeople() []string {
 	if x != nil {
-		return x.Id
+		return x.People
 	}
-	return ""
+	return nil
 }
 
-// The request message containing the user's name.
-type AddressRequest struct {
+// MergeDevicesRequest folds the sources into the target device and deletes them
+type MergeDevicesRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Ip       string   `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
-	Mac      string   `protobuf:"bytes,2,opt,name=mac,proto3" json:"mac,omitempty"`
-	Distance float32  `protobuf:"fixed32,3,opt,name=distance,proto3" json:"distance,omitempty"`
-	Hosts    []string `protobuf:"bytes,4,rep,name=hosts,proto3" json:"hosts,omitempty"`
-	Vendor   string   `protobuf:"bytes,5,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Target  string   `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Sources []string `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
 }
 
-func (x *AddressRequest) Reset() {
-	*x = AddressRequest{}
+func (x *MergeDevicesRequest) Reset() {
+	*x = MergeDevicesRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_DeviceDetector_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -910,13 +986,13 @@ func (x *AddressRequest) Reset() {
 	}
 }
 
-func (x *AddressRequest) String() string {
+func (x *MergeDevicesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddressRequest) ProtoMessage() {}
+func (*MergeDevicesRequest) ProtoMessage() {}
 
-func (x *AddressRequest) ProtoReflect() protoreflect.Message {
+func (x *MergeDevicesRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_DeviceDetector_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -928,56 +1004,41 @@ func (x *AddressRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddressRequest.ProtoReflect.Descriptor instead.
-func (*AddressRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use MergeDevicesRequest.ProtoReflect.Descriptor instead.
+func (*MergeDevicesRequest) Descriptor() ([]byte, []int) {
 	return file_DeviceDetector_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *AddressRequest) GetIp() string {
-	if x != nil {
-		return x.Ip
-	}
-	return ""
-}
-
-func (x *AddressRequest) GetMac() string {
+func (x *MergeDevicesRequest) GetTarget() string {
 	if x != nil {
-		return x.Mac
+		return x.Target
 	}
 	return ""
 }
 
-func (x *AddressRequest) GetDistance() float32 {
-	if x != nil {
-		return x.Distance
-	}
-	return 0
-}
-
-func (x *AddressRequest) GetHosts() []string {
+func (x *MergeDevicesRequest) GetSources() []string {
 	if x != nil {
-		return x.Hosts
+		return x.Sources
 	}
 	return nil
 }
 
-func (x *AddressRequest) GetVendor() string {
-	if x != nil {
-		return x.Vendor
-	}
-	return ""
-}
-
-type AddressesRequest struct {
+// Bundle is a snapshot of the stored state written by admin export
+type Bundle struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Addresses []*AddressRequest `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Version  int32            `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Devices  []*Devices       `protobuf:"bytes,2,rep,name=devices,proto3" json:"devices,omitempty"`
+	Bles     []*BleDevices    `protobuf:"bytes,3,rep,name=bles,proto3" json:"bles,omitempty"`
+	People   []*People        `protobuf:"bytes,4,rep,name=people,proto3" json:"people,omitempty"`
+	Homes    []*Home          `protobuf:"bytes,5,rep,name=homes,proto3" json:"homes,omitempty"`
+	Commands []*TimedCommands `protobuf:"bytes,6,rep,name=commands,proto3" json:"commands,omitempty"`
 }
 
-func (x *AddressesRequest) Reset() {
-	*x = AddressesRequest{}
+func (x *Bundle) Reset() {
+	*x = Bundle{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_DeviceDetector_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -985,13 +1046,13 @@ func (x *AddressesRequest) Reset() {
 	}
 }
 
-func (x *AddressesRequest) String() string {
+func (x *Bundle) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddressesRequest) ProtoMessage() {}
+func (*Bundle) ProtoMessage() {}
 
-func (x *AddressesRequest) ProtoReflect() protoreflect.Message {
+func (x *Bundle) ProtoReflect() protoreflect.Message {
 	mi := &file_DeviceDetector_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1003,29 +1064,63 @@ func (x *AddressesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddressesRequest.ProtoReflect.Descriptor instead.
-func (*AddressesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Bundle.ProtoReflect.Descriptor instead.
+func (*Bundle) Descriptor() ([]byte, []int) {
 	return file_DeviceDetector_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *AddressesRequest) GetAddresses() []*AddressRequest {
+func (x *Bundle) GetVersion() int32 {
 	if x != nil {
-		return x.Addresses
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Bundle) GetDevices() []*Devices {
+	if x != nil {
+		return x.Devices
 	}
 	return nil
 }
 
-// The response message containing the greetings
-type Reply struct {
+func (x *Bundle) GetBles() []*BleDevices {
+	if x != nil {
+		return x.Bles
+	}
+	return nil
+}
+
+func (x *Bundle) GetPeople() []*People {
+	if x != nil {
+		return x.People
+	}
+	return nil
+}
+
+func (x *Bundle) GetHomes() []*Home {
+	if x != nil {
+		return x.Homes
+	}
+	return nil
+}
+
+func (x *Bundle) GetCommands() []*TimedCommands {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+type HomesResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Acknowledged bool `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	Homes []*Home `protobuf:"bytes,1,rep,name=homes,proto3" json:"homes,omitempty"`
 }
 
-func (x *Reply) Reset() {
-	*x = Reply{}
+func (x *HomesResponse) Reset() {
+	*x = HomesResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_DeviceDetector_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1033,13 +1128,13 @@ func (x *Reply) Reset() {
 	}
 }
 
-func (x *Reply) String() string {
+func (x *HomesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Reply) ProtoMessage() {}
+func (*HomesResponse) ProtoMessage() {}
 
-func (x *Reply) ProtoReflect() protoreflect.Message {
+func (x *HomesResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_DeviceDetector_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1051,28 +1146,38 @@ func (x *Reply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Reply.ProtoReflect.Descriptor instead.
-func (*Reply) Descriptor() ([]byte, []int) {
+// Deprecated: Use HomesResponse.ProtoReflect.Descriptor instead.
+func (*HomesResponse) Descriptor() ([]byte, []int) {
 	return file_DeviceDetector_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *Reply) GetAcknowledged() bool {
+func (x *HomesResponse) GetHomes() []*Home {
 	if x != nil {
-		return x.Acknowledged
+		return x.Homes
 	}
-	return false
+	return nil
 }
 
-type PeopleResponse struct {
+type BleDevices struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	People []*People `protobuf:"bytes,1,rep,name=people,proto3" json:"people,omitempty"`
+	Id          string      `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	LastSeen    int64       `protobuf:"varint,2,opt,name=LastSeen,proto3" json:"LastSeen,omitempty"`
+	Commands    []*Commands `protobuf:"bytes,3,rep,name=commands,proto3" json:"commands,omitempty"`
+	Name        string      `protobuf:"bytes,4,opt,name=Name,proto3" json:"Name,omitempty"`
+	Home        string      `protobuf:"bytes,5,opt,name=Home,proto3" json:"Home,omitempty"`
+	Tile        bool        `protobuf:"varint,6,opt,name=tile,proto3" json:"tile,omitempty"`
+	Distance    float32     `protobuf:"fixed32,7,opt,name=distance,proto3" json:"distance,omitempty"`
+	Metadata    []*Metadata `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	AwayTimeout int64       `protobuf:"varint,9,opt,name=AwayTimeout,proto3" json:"AwayTimeout,omitempty"`
+	// Reported is the agent's timestamp of the latest MQTT report, older reports are late
+	Reported int64 `protobuf:"varint,10,opt,name=Reported,proto3" json:"Reported,omitempty"`
 }
 
-func (x *PeopleResponse) Reset() {
-	*x = PeopleResponse{}
+func (x *BleDevices) Reset() {
+	*x = BleDevices{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_DeviceDetector_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1080,13 +1185,13 @@ func (x *PeopleResponse) Reset() {
 	}
 }
 
-func (x *PeopleResponse) String() string {
+func (x *BleDevices) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PeopleResponse) ProtoMessage() {}
+func (*BleDevices) ProtoMessage() {}
 
-func (x *PeopleResponse) ProtoReflect() protoreflect.Message {
+func (x *BleDevices) ProtoReflect() protoreflect.Message {
 	mi := &file_DeviceDetector_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1098,46 +1203,952 @@ func (x *PeopleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PeopleResponse.ProtoReflect.Descriptor instead.
-func (*PeopleResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BleDevices.ProtoReflect.Descriptor instead.
+func (*BleDevices) Descriptor() ([]byte, []int) {
 	return file_DeviceDetector_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *PeopleResponse) GetPeople() []*People {
+func (x *BleDevices) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BleDevices) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *BleDevices) GetCommands() []*Commands {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+func (x *BleDevices) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BleDevices) GetHome() string {
+	if x != nil {
+		return x.Home
+	}
+	return ""
+}
+
+func (x *BleDevices) GetTile() bool {
+	if x != nil {
+		return x.Tile
+	}
+	return false
+}
+
+func (x *BleDevices) GetDistance() float32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *BleDevices) GetMetadata() []*Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *BleDevices) GetAwayTimeout() int64 {
+	if x != nil {
+		return x.AwayTimeout
+	}
+	return 0
+}
+
+func (x *BleDevices) GetReported() int64 {
+	if x != nil {
+		return x.Reported
+	}
+	return 0
+}
+
+type Commands struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timeout int64  `protobuf:"varint,1,opt,name=Timeout,proto3" json:"Timeout,omitempty"`
+	Command string `protobuf:"bytes,2,opt,name=Command,proto3" json:"Command,omitempty"`
+	Id      string `protobuf:"bytes,3,opt,name=Id,proto3" json:"Id,omitempty"`
+}
+
+func (x *Commands) Reset() {
+	*x = Commands{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Commands) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Commands) ProtoMessage() {}
+
+func (x *Commands) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Commands.ProtoReflect.Descriptor instead.
+func (*Commands) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Commands) GetTimeout() int64 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *Commands) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Commands) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// The request message containing the user's name.
+type AddressRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Ip       string   `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Mac      string   `protobuf:"bytes,2,opt,name=mac,proto3" json:"mac,omitempty"`
+	Distance float32  `protobuf:"fixed32,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	Hosts    []string `protobuf:"bytes,4,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	Vendor   string   `protobuf:"bytes,5,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	// mDNS service types the host advertises
+	Services []string `protobuf:"bytes,6,rep,name=services,proto3" json:"services,omitempty"`
+	// open_ports are only reported by service scans, ports_scanned tells an empty list apart from no scan
+	OpenPorts    []*OpenPort `protobuf:"bytes,7,rep,name=open_ports,json=openPorts,proto3" json:"open_ports,omitempty"`
+	PortsScanned bool        `protobuf:"varint,8,opt,name=ports_scanned,json=portsScanned,proto3" json:"ports_scanned,omitempty"`
+	// os is the best match of nmap OS detection, only reported by service scans with -os-detection
+	Os *OSMatch `protobuf:"bytes,9,opt,name=os,proto3" json:"os,omitempty"`
+}
+
+func (x *AddressRequest) Reset() {
+	*x = AddressRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressRequest) ProtoMessage() {}
+
+func (x *AddressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressRequest.ProtoReflect.Descriptor instead.
+func (*AddressRequest) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AddressRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AddressRequest) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *AddressRequest) GetDistance() float32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *AddressRequest) GetHosts() []string {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+func (x *AddressRequest) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *AddressRequest) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *AddressRequest) GetOpenPorts() []*OpenPort {
+	if x != nil {
+		return x.OpenPorts
+	}
+	return nil
+}
+
+func (x *AddressRequest) GetPortsScanned() bool {
+	if x != nil {
+		return x.PortsScanned
+	}
+	return false
+}
+
+func (x *AddressRequest) GetOs() *OSMatch {
+	if x != nil {
+		return x.Os
+	}
+	return nil
+}
+
+type AddressesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Addresses []*AddressRequest `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+}
+
+func (x *AddressesRequest) Reset() {
+	*x = AddressesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddressesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddressesRequest) ProtoMessage() {}
+
+func (x *AddressesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddressesRequest.ProtoReflect.Descriptor instead.
+func (*AddressesRequest) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AddressesRequest) GetAddresses() []*AddressRequest {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+// The response message containing the greetings
+type Reply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Acknowledged bool `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+}
+
+func (x *Reply) Reset() {
+	*x = Reply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Reply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reply) ProtoMessage() {}
+
+func (x *Reply) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reply.ProtoReflect.Descriptor instead.
+func (*Reply) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *Reply) GetAcknowledged() bool {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return false
+}
+
+type PeopleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	People []*People `protobuf:"bytes,1,rep,name=people,proto3" json:"people,omitempty"`
+}
+
+func (x *PeopleResponse) Reset() {
+	*x = PeopleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeopleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeopleResponse) ProtoMessage() {}
+
+func (x *PeopleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeopleResponse.ProtoReflect.Descriptor instead.
+func (*PeopleResponse) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PeopleResponse) GetPeople() []*People {
+	if x != nil {
+		return x.People
+	}
+	return nil
+}
+
+type People struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string   `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Ids  []string `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	Away bool     `protobuf:"varint,3,opt,name=away,proto3" json:"away,omitempty"`
+	Home string   `protobuf:"bytes,4,opt,name=home,proto3" json:"home,omitempty"`
+}
+
+func (x *People) Reset() {
+	*x = People{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *People) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*People) ProtoMessage() {}
+
+func (x *People) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use People.ProtoReflect.Descriptor instead.
+func (*People) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *People) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *People) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *People) GetAway() bool {
+	if x != nil {
+		return x.Away
+	}
+	return false
+}
+
+func (x *People) GetHome() string {
+	if x != nil {
+		return x.Home
+	}
+	return ""
+}
+
+// WatchPresenceRequest filters the presence events, blank fields match everything
+type WatchPresenceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Home string `protobuf:"bytes,1,opt,name=home,proto3" json:"home,omitempty"`
+	// device type eg network or ble, events without a device aren't filtered by it
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// replay the buffered events after this id before streaming new ones
+	LastEventId uint64 `protobuf:"varint,3,opt,name=lastEventId,proto3" json:"lastEventId,omitempty"`
+}
+
+func (x *WatchPresenceRequest) Reset() {
+	*x = WatchPresenceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchPresenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPresenceRequest) ProtoMessage() {}
+
+func (x *WatchPresenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPresenceRequest.ProtoReflect.Descriptor instead.
+func (*WatchPresenceRequest) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *WatchPresenceRequest) GetHome() string {
+	if x != nil {
+		return x.Home
+	}
+	return ""
+}
+
+func (x *WatchPresenceRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *WatchPresenceRequest) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type PresenceEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type      PresenceEventType `protobuf:"varint,1,opt,name=type,proto3,enum=proto.PresenceEventType" json:"type,omitempty"`
+	Home      string            `protobuf:"bytes,2,opt,name=home,proto3" json:"home,omitempty"`
+	Timestamp int64             `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Device    *Devices          `protobuf:"bytes,4,opt,name=device,proto3" json:"device,omitempty"`
+	Ble       *BleDevices       `protobuf:"bytes,5,opt,name=ble,proto3" json:"ble,omitempty"`
+	Person    *People           `protobuf:"bytes,6,opt,name=person,proto3" json:"person,omitempty"`
+	// set for HOUSE_EMPTY_CHANGED
+	Empty bool `protobuf:"varint,7,opt,name=empty,proto3" json:"empty,omitempty"`
+	// set for DEVICE_MOVED_HOME
+	PreviousHome string `protobuf:"bytes,8,opt,name=previousHome,proto3" json:"previousHome,omitempty"`
+	// increasing sequence number, resume a watch after it with lastEventId
+	Id uint64 `protobuf:"varint,9,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *PresenceEvent) Reset() {
+	*x = PresenceEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PresenceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceEvent) ProtoMessage() {}
+
+func (x *PresenceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceEvent.ProtoReflect.Descriptor instead.
+func (*PresenceEvent) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PresenceEvent) GetType() PresenceEventType {
+	if x != nil {
+		return x.Type
+	}
+	return PresenceEventType_UNKNOWN
+}
+
+func (x *PresenceEvent) GetHome() string {
+	if x != nil {
+		return x.Home
+	}
+	return ""
+}
+
+func (x *PresenceEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PresenceEvent) GetDevice() *Devices {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *PresenceEvent) GetBle() *BleDevices {
+	if x != nil {
+		return x.Ble
+	}
+	return nil
+}
+
+func (x *PresenceEvent) GetPerson() *People {
+	if x != nil {
+		return x.Person
+	}
+	return nil
+}
+
+func (x *PresenceEvent) GetEmpty() bool {
+	if x != nil {
+		return x.Empty
+	}
+	return false
+}
+
+func (x *PresenceEvent) GetPreviousHome() string {
+	if x != nil {
+		return x.PreviousHome
+	}
+	return ""
+}
+
+func (x *PresenceEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+// AssignDeviceRequest gives the network or ble device id to the named person
+type AssignDeviceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Person string `protobuf:"bytes,1,opt,name=person,proto3" json:"person,omitempty"`
+	Id     string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *AssignDeviceRequest) Reset() {
+	*x = AssignDeviceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AssignDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignDeviceRequest) ProtoMessage() {}
+
+func (x *AssignDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignDeviceRequest.ProtoReflect.Descriptor instead.
+func (*AssignDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AssignDeviceRequest) GetPerson() string {
+	if x != nil {
+		return x.Person
+	}
+	return ""
+}
+
+func (x *AssignDeviceRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Devices struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id            *NetworkId  `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Home          string      `protobuf:"bytes,2,opt,name=Home,proto3" json:"Home,omitempty"`
+	LastSeen      int64       `protobuf:"varint,3,opt,name=LastSeen,proto3" json:"LastSeen,omitempty"`
+	Away          bool        `protobuf:"varint,4,opt,name=Away,proto3" json:"Away,omitempty"`
+	Name          string      `protobuf:"bytes,5,opt,name=Name,proto3" json:"Name,omitempty"`
+	Person        bool        `protobuf:"varint,6,opt,name=Person,proto3" json:"Person,omitempty"`
+	Command       string      `protobuf:"bytes,7,opt,name=Command,proto3" json:"Command,omitempty"`
+	Smart         bool        `protobuf:"varint,8,opt,name=Smart,proto3" json:"Smart,omitempty"`
+	Manufacturer  string      `protobuf:"bytes,9,opt,name=Manufacturer,proto3" json:"Manufacturer,omitempty"`
+	PresenceAware bool        `protobuf:"varint,10,opt,name=PresenceAware,proto3" json:"PresenceAware,omitempty"`
+	Latency       float32     `protobuf:"fixed32,11,opt,name=Latency,proto3" json:"Latency,omitempty"`
+	Hostnames     []string    `protobuf:"bytes,12,rep,name=Hostnames,proto3" json:"Hostnames,omitempty"`
+	Metadata      []*Metadata `protobuf:"bytes,13,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	AwayTimeout   int64       `protobuf:"varint,14,opt,name=AwayTimeout,proto3" json:"AwayTimeout,omitempty"`
+	// Randomized is set for locally administered MACs, phones rotate these per network
+	Randomized bool `protobuf:"varint,15,opt,name=Randomized,proto3" json:"Randomized,omitempty"`
+	// Category is assigned by the category rules eg phone, tv, printer, iot or infrastructure
+	Category  string      `protobuf:"bytes,16,opt,name=Category,proto3" json:"Category,omitempty"`
+	OpenPorts []*OpenPort `protobuf:"bytes,17,rep,name=OpenPorts,proto3" json:"OpenPorts,omitempty"`
+	// Services are the mDNS service types the device advertises eg _googlecast._tcp
+	Services []string `protobuf:"bytes,18,rep,name=Services,proto3" json:"Services,omitempty"`
+	OS       *OSMatch `protobuf:"bytes,19,opt,name=OS,proto3" json:"OS,omitempty"`
+	// SeenPorts are every port a service scan has found open, only ports missing from them are alerted on
+	SeenPorts []*OpenPort `protobuf:"bytes,20,rep,name=SeenPorts,proto3" json:"SeenPorts,omitempty"`
+	// Reported is the agent's timestamp of the latest MQTT report, older reports are late
+	Reported int64 `protobuf:"varint,21,opt,name=Reported,proto3" json:"Reported,omitempty"`
+}
+
+func (x *Devices) Reset() {
+	*x = Devices{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_DeviceDetector_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Devices) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Devices) ProtoMessage() {}
+
+func (x *Devices) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Devices.ProtoReflect.Descriptor instead.
+func (*Devices) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Devices) GetId() *NetworkId {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Devices) GetHome() string {
+	if x != nil {
+		return x.Home
+	}
+	return ""
+}
+
+func (x *Devices) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *Devices) GetAway() bool {
+	if x != nil {
+		return x.Away
+	}
+	return false
+}
+
+func (x *Devices) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Devices) GetPerson() bool {
+	if x != nil {
+		return x.Person
+	}
+	return false
+}
+
+func (x *Devices) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Devices) GetSmart() bool {
+	if x != nil {
+		return x.Smart
+	}
+	return false
+}
+
+func (x *Devices) GetManufacturer() string {
+	if x != nil {
+		return x.Manufacturer
+	}
+	return ""
+}
+
+func (x *Devices) GetPresenceAware() bool {
+	if x != nil {
+		return x.PresenceAware
+	}
+	return false
+}
+
+func (x *Devices) GetLatency() float32 {
+	if x != nil {
+		return x.Latency
+	}
+	return 0
+}
+
+func (x *Devices) GetHostnames() []string {
+	if x != nil {
+		return x.Hostnames
+	}
+	return nil
+}
+
+func (x *Devices) GetMetadata() []*Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Devices) GetAwayTimeout() int64 {
+	if x != nil {
+		return x.AwayTimeout
+	}
+	return 0
+}
+
+func (x *Devices) GetRandomized() bool {
+	if x != nil {
+		return x.Randomized
+	}
+	return false
+}
+
+func (x *Devices) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Devices) GetOpenPorts() []*OpenPort {
+	if x != nil {
+		return x.OpenPorts
+	}
+	return nil
+}
+
+func (x *Devices) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *Devices) GetOS() *OSMatch {
+	if x != nil {
+		return x.OS
+	}
+	return nil
+}
+
+func (x *Devices) GetSeenPorts() []*OpenPort {
 	if x != nil {
-		return x.People
+		return x.SeenPorts
 	}
 	return nil
 }
 
-type People struct {
+func (x *Devices) GetReported() int64 {
+	if x != nil {
+		return x.Reported
+	}
+	return 0
+}
+
+// OSMatch is the operating system nmap fingerprinted a device as
+type OSMatch struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name string   `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
-	Ids  []string `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
-	Away bool     `protobuf:"varint,3,opt,name=away,proto3" json:"away,omitempty"`
-	Home string   `protobuf:"bytes,4,opt,name=home,proto3" json:"home,omitempty"`
+	// name is nmap's description eg Linux 4.15 - 5.8
+	Name       string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Family     string `protobuf:"bytes,2,opt,name=family,proto3" json:"family,omitempty"`
+	Generation string `protobuf:"bytes,3,opt,name=generation,proto3" json:"generation,omitempty"`
+	Vendor     string `protobuf:"bytes,4,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	// accuracy is nmap's confidence in the match as a percentage
+	Accuracy int32 `protobuf:"varint,5,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
 }
 
-func (x *People) Reset() {
-	*x = People{}
+func (x *OSMatch) Reset() {
+	*x = OSMatch{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_DeviceDetector_proto_msgTypes[18]
+		mi := &file_DeviceDetector_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *People) String() string {
+func (x *OSMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*People) ProtoMessage() {}
+func (*OSMatch) ProtoMessage() {}
 
-func (x *People) ProtoReflect() protoreflect.Message {
-	mi := &file_DeviceDetector_proto_msgTypes[18]
+func (x *OSMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1148,76 +2159,77 @@ func (x *People) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use People.ProtoReflect.Descriptor instead.
-func (*People) Descriptor() ([]byte, []int) {
-	return file_DeviceDetector_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use OSMatch.ProtoReflect.Descriptor instead.
+func (*OSMatch) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *People) GetName() string {
+func (x *OSMatch) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-func (x *People) GetIds() []string {
+func (x *OSMatch) GetFamily() string {
 	if x != nil {
-		return x.Ids
+		return x.Family
 	}
-	return nil
+	return ""
 }
 
-func (x *People) GetAway() bool {
+func (x *OSMatch) GetGeneration() string {
 	if x != nil {
-		return x.Away
+		return x.Generation
 	}
-	return false
+	return ""
 }
 
-func (x *People) GetHome() string {
+func (x *OSMatch) GetVendor() string {
 	if x != nil {
-		return x.Home
+		return x.Vendor
 	}
 	return ""
 }
 
-type Devices struct {
+func (x *OSMatch) GetAccuracy() int32 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+// OpenPort is a port found open on a device
+type OpenPort struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id            *NetworkId  `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
-	Home          string      `protobuf:"bytes,2,opt,name=Home,proto3" json:"Home,omitempty"`
-	LastSeen      int64       `protobuf:"varint,3,opt,name=LastSeen,proto3" json:"LastSeen,omitempty"`
-	Away          bool        `protobuf:"varint,4,opt,name=Away,proto3" json:"Away,omitempty"`
-	Name          string      `protobuf:"bytes,5,opt,name=Name,proto3" json:"Name,omitempty"`
-	Person        bool        `protobuf:"varint,6,opt,name=Person,proto3" json:"Person,omitempty"`
-	Command       string      `protobuf:"bytes,7,opt,name=Command,proto3" json:"Command,omitempty"`
-	Smart         bool        `protobuf:"varint,8,opt,name=Smart,proto3" json:"Smart,omitempty"`
-	Manufacturer  string      `protobuf:"bytes,9,opt,name=Manufacturer,proto3" json:"Manufacturer,omitempty"`
-	PresenceAware bool        `protobuf:"varint,10,opt,name=PresenceAware,proto3" json:"PresenceAware,omitempty"`
-	Latency       float32     `protobuf:"fixed32,11,opt,name=Latency,proto3" json:"Latency,omitempty"`
-	Hostnames     []string    `protobuf:"bytes,12,rep,name=Hostnames,proto3" json:"Hostnames,omitempty"`
-	Metadata      []*Metadata `protobuf:"bytes,13,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	Port     int32  `protobuf:"varint,1,opt,name=port,proto3" json:"port,omitempty"`
+	Protocol string `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Service  string `protobuf:"bytes,3,opt,name=service,proto3" json:"service,omitempty"`
+	Banner   string `protobuf:"bytes,4,opt,name=banner,proto3" json:"banner,omitempty"`
+	// first_seen is the unix time a service scan first found the port open
+	FirstSeen int64 `protobuf:"varint,5,opt,name=first_seen,json=firstSeen,proto3" json:"first_seen,omitempty"`
 }
 
-func (x *Devices) Reset() {
-	*x = Devices{}
+func (x *OpenPort) Reset() {
+	*x = OpenPort{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_DeviceDetector_proto_msgTypes[19]
+		mi := &file_DeviceDetector_proto_msgTypes[29]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Devices) String() string {
+func (x *OpenPort) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Devices) ProtoMessage() {}
+func (*OpenPort) ProtoMessage() {}
 
-func (x *Devices) ProtoReflect() protoreflect.Message {
-	mi := &file_DeviceDetector_proto_msgTypes[19]
+func (x *OpenPort) ProtoReflect() protoreflect.Message {
+	mi := &file_DeviceDetector_proto_msgTypes[29]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1228,102 +2240,46 @@ func (x *Devices) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Devices.ProtoReflect.Descriptor instead.
-func (*Devices) Descriptor() ([]byte, []int) {
-	return file_DeviceDetector_proto_rawDescGZIP(), []int{19}
-}
-
-func (x *Devices) GetId() *NetworkId {
-	if x != nil {
-		return x.Id
-	}
-	return nil
-}
-
-func (x *Devices) GetHome() string {
-	if x != nil {
-		return x.Home
-	}
-	return ""
+// Deprecated: Use OpenPort.ProtoReflect.Descriptor instead.
+func (*OpenPort) Descriptor() ([]byte, []int) {
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *Devices) GetLastSeen() int64 {
+func (x *OpenPort) GetPort() int32 {
 	if x != nil {
-		return x.LastSeen
+		return x.Port
 	}
 	return 0
 }
 
-func (x *Devices) GetAway() bool {
-	if x != nil {
-		return x.Away
-	}
-	return false
-}
-
-func (x *Devices) GetName() string {
+func (x *OpenPort) GetProtocol() string {
 	if x != nil {
-		return x.Name
+		return x.Protocol
 	}
 	return ""
 }
 
-func (x *Devices) GetPerson() bool {
-	if x != nil {
-		return x.Person
-	}
-	return false
-}
-
-func (x *Devices) GetCommand() string {
+func (x *OpenPort) GetService() string {
 	if x != nil {
-		return x.Command
+		return x.Service
 	}
 	return ""
 }
 
-func (x *Devices) GetSmart() bool {
-	if x != nil {
-		return x.Smart
-	}
-	return false
-}
-
-func (x *Devices) GetManufacturer() string {
+func (x *OpenPort) GetBanner() string {
 	if x != nil {
-		return x.Manufacturer
+		return x.Banner
 	}
 	return ""
 }
 
-func (x *Devices) GetPresenceAware() bool {
-	if x != nil {
-		return x.PresenceAware
-	}
-	return false
-}
-
-func (x *Devices) GetLatency() float32 {
+func (x *OpenPort) GetFirstSeen() int64 {
 	if x != nil {
-		return x.Latency
+		return x.FirstSeen
 	}
 	return 0
 }
 
-func (x *Devices) GetHostnames() []string {
-	if x != nil {
-		return x.Hostnames
-	}
-	return nil
-}
-
-func (x *Devices) GetMetadata() []*Metadata {
-	if x != nil {
-		return x.Metadata
-	}
-	return nil
-}
-
 type NetworkId struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1337,7 +2293,7 @@ type NetworkId struct {
 func (x *NetworkId) Reset() {
 	*x = NetworkId{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_DeviceDetector_proto_msgTypes[20]
+		mi := &file_DeviceDetector_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1350,7 +2306,7 @@ func (x *NetworkId) String() string {
 func (*NetworkId) ProtoMessage() {}
 
 func (x *NetworkId) ProtoReflect() protoreflect.Message {
-	mi := &file_DeviceDetector_proto_msgTypes[20]
+	mi := &file_DeviceDetector_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1363,7 +2319,7 @@ func (x *NetworkId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NetworkId.ProtoReflect.Descriptor instead.
 func (*NetworkId) Descriptor() ([]byte, []int) {
-	return file_DeviceDetector_proto_rawDescGZIP(), []int{20}
+	return file_DeviceDetector_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *NetworkId) GetIp() string {
@@ -1391,204 +2347,408 @@ var File_DeviceDetector_proto protoreflect.FileDescriptor
 
 var file_DeviceDetector_proto_rawDesc = []byte{
 	0x0a, 0x14, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x6f, 0x72,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65,
-	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x21, 0x0a, 0x0d, 0x53, 0x74,
-	0x72, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x3a, 0x0a,
-	0x0a, 0x42, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a,
-	0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52,
-	0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x5f, 0x0a, 0x13, 0x47, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x41, 0x73, 0x73, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x43, 0x61, 0x6c, 0x6c,
-	0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x75, 0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1a,
-	0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x08, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x65, 0x22, 0x49, 0x0a, 0x07, 0x46, 0x43,
-	0x4d, 0x43, 0x61, 0x6c, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x62,
-	0x6f, 0x64, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x69, 0x6d, 0x61, 0x67, 0x65, 0x22, 0xbc, 0x01, 0x0a, 0x12, 0x4d, 0x51, 0x54, 0x54, 0x41, 0x64,
-	0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x05,
-	0x61, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x51, 0x54, 0x54, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x61,
-	0x67, 0x65, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09,
-	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d,
-	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61,
-	0x64, 0x61, 0x74, 0x61, 0x22, 0x8c, 0x01, 0x0a, 0x0e, 0x4d, 0x51, 0x54, 0x54, 0x42, 0x6c, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x61, 0x67, 0x65, 0x6e, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d,
-	0x51, 0x54, 0x54, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x12,
-	0x25, 0x0a, 0x04, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x52, 0x04, 0x62, 0x6c, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x22, 0x2f, 0x0a, 0x09, 0x4d, 0x51, 0x54, 0x54, 0x41, 0x67, 0x65, 0x6e, 0x74,
-	0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x68, 0x6f, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x32, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x89, 0x01, 0x0a, 0x0d, 0x54, 0x69, 0x6d,
-	0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78,
-	0x65, 0x63, 0x75, 0x74, 0x65, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65,
-	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65,
-	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x18,
-	0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x65, 0x63,
-	0x75, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x78, 0x65, 0x63,
-	0x75, 0x74, 0x65, 0x64, 0x22, 0x35, 0x0a, 0x0b, 0x43, 0x51, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x03, 0x63, 0x71, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1c, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70,
+	0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x21, 0x0a, 0x0d, 0x53, 0x74, 0x72, 0x69,
+	0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x3a, 0x0a, 0x0a, 0x42,
+	0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x64,
+	0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x64,
+	0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x5f, 0x0a, 0x13, 0x47, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x41, 0x73, 0x73, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x12, 0x12,
+	0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08,
+	0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x65, 0x22, 0x49, 0x0a, 0x07, 0x46, 0x43, 0x4d, 0x43,
+	0x61, 0x6c, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6d,
+	0x61, 0x67, 0x65, 0x22, 0xbc, 0x01, 0x0a, 0x12, 0x4d, 0x51, 0x54, 0x54, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x61, 0x67,
+	0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4d, 0x51, 0x54, 0x54, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x12, 0x33, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x22, 0xaa, 0x01, 0x0a, 0x0e, 0x4d, 0x51, 0x54, 0x54, 0x42, 0x6c, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x05, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x51, 0x54,
+	0x54, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x52, 0x05, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a,
+	0x04, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x04,
+	0x62, 0x6c, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x22,
+	0x2f, 0x0a, 0x09, 0x4d, 0x51, 0x54, 0x54, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x68, 0x6f, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x6d, 0x65,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x32, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x22, 0x89, 0x01, 0x0a, 0x0d, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x65, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x65, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x64,
+	0x22, 0x35, 0x0a, 0x0b, 0x43, 0x51, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x26, 0x0a, 0x03, 0x63, 0x71, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x73, 0x52, 0x03, 0x63, 0x71, 0x73, 0x22, 0x34, 0x0a, 0x0b, 0x54, 0x43, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x65,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x04, 0x62, 0x6c, 0x65, 0x73, 0x22, 0xe6, 0x01,
+	0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x73,
+	0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x06, 0x70, 0x65, 0x72, 0x73,
+	0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x61, 0x77, 0x61, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x04, 0x61, 0x77, 0x61, 0x79, 0x88, 0x01, 0x01, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72,
+	0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6d, 0x61, 0x6e, 0x75, 0x66, 0x61,
+	0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x61, 0x77, 0x61, 0x79, 0x22, 0x63, 0x0a, 0x0f, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x07, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65,
+	0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x48, 0x0a, 0x04, 0x48,
+	0x6f, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x70, 0x74, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x70,
+	0x65, 0x6f, 0x70, 0x6c, 0x65, 0x22, 0x47, 0x0a, 0x13, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x74, 0x61,
+	0x72, 0x67, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0xef,
+	0x01, 0x0a, 0x06, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x28, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x73, 0x52, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x25, 0x0a,
+	0x04, 0x62, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x04,
+	0x62, 0x6c, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x18, 0x04,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x65, 0x6f,
+	0x70, 0x6c, 0x65, 0x52, 0x06, 0x70, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x68,
+	0x6f, 0x6d, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x48, 0x6f, 0x6d, 0x65, 0x52, 0x05, 0x68, 0x6f, 0x6d, 0x65, 0x73, 0x12, 0x30,
+	0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
 	0x32, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f,
-	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x52, 0x03, 0x63, 0x71, 0x73, 0x22, 0x34, 0x0a, 0x0b, 0x54,
-	0x43, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x62, 0x6c,
-	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x42, 0x6c, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x04, 0x62, 0x6c, 0x65,
-	0x73, 0x22, 0x3b, 0x0a, 0x0f, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x07, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x22, 0xea,
-	0x01, 0x0a, 0x0a, 0x42, 0x6c, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x0e, 0x0a,
-	0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x1a, 0x0a,
-	0x08, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x08, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x2b, 0x0a, 0x08, 0x63, 0x6f, 0x6d,
-	0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x52, 0x08, 0x63, 0x6f,
-	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x6f,
-	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x48, 0x6f, 0x6d, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x74, 0x69,
-	0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x2b,
-	0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x4e, 0x0a, 0x08, 0x43,
-	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x54, 0x69, 0x6d, 0x65, 0x6f,
-	0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75,
-	0x74, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x49,
-	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x22, 0x7c, 0x0a, 0x0e, 0x41,
-	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70, 0x12, 0x10, 0x0a,
-	0x03, 0x6d, 0x61, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x61, 0x63, 0x12,
-	0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x02, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x68,
-	0x6f, 0x73, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x68, 0x6f, 0x73, 0x74,
-	0x73, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x22, 0x47, 0x0a, 0x10, 0x41, 0x64, 0x64,
-	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a,
-	0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
-	0x65, 0x73, 0x22, 0x2b, 0x0a, 0x05, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x61,
-	0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0c, 0x61, 0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c, 0x65, 0x64, 0x67, 0x65, 0x64, 0x22,
-	0x37, 0x0a, 0x0e, 0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65,
-	0x52, 0x06, 0x70, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x22, 0x56, 0x0a, 0x06, 0x50, 0x65, 0x6f, 0x70,
-	0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x77, 0x61, 0x79,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x61, 0x77, 0x61, 0x79, 0x12, 0x12, 0x0a, 0x04,
-	0x68, 0x6f, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x6d, 0x65,
-	0x22, 0xfa, 0x02, 0x0a, 0x07, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x02,
-	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x64, 0x52, 0x02, 0x49, 0x64, 0x12, 0x12,
-	0x0a, 0x04, 0x48, 0x6f, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x48, 0x6f,
-	0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x12,
-	0x0a, 0x04, 0x41, 0x77, 0x61, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x41, 0x77,
-	0x61, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x18,
-	0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x6d, 0x61, 0x72,
-	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x53, 0x6d, 0x61, 0x72, 0x74, 0x12, 0x22,
-	0x0a, 0x0c, 0x4d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x4d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72,
-	0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x41, 0x77,
-	0x61, 0x72, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x50, 0x72, 0x65, 0x73, 0x65,
-	0x6e, 0x63, 0x65, 0x41, 0x77, 0x61, 0x72, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x4c, 0x61, 0x74, 0x65,
-	0x6e, 0x63, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x4c, 0x61, 0x74, 0x65, 0x6e,
-	0x63, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18,
-	0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73,
-	0x12, 0x2b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x0d, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0x41, 0x0a,
-	0x09, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x70,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x61,
-	0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4d, 0x61, 0x63, 0x12, 0x12, 0x0a, 0x04,
-	0x55, 0x55, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x55, 0x55, 0x49, 0x44,
-	0x32, 0xa0, 0x07, 0x0a, 0x0c, 0x48, 0x6f, 0x6d, 0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x6f,
-	0x72, 0x12, 0x28, 0x0a, 0x03, 0x41, 0x63, 0x6b, 0x12, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x42, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x07, 0x41,
-	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41,
-	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x34, 0x0a,
-	0x09, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x64,
-	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x1a, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x43, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f,
-	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x1a, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x51, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74,
-	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
+	0x22, 0x32, 0x0a, 0x0d, 0x48, 0x6f, 0x6d, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x21, 0x0a, 0x05, 0x68, 0x6f, 0x6d, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x48, 0x6f, 0x6d, 0x65, 0x52, 0x05, 0x68,
+	0x6f, 0x6d, 0x65, 0x73, 0x22, 0xa8, 0x02, 0x0a, 0x0a, 0x42, 0x6c, 0x65, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x4c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12,
+	0x2b, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x73, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x48, 0x6f, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x48, 0x6f, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x04, 0x74, 0x69, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x12, 0x2b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x20, 0x0a, 0x0b, 0x41, 0x77, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x41, 0x77, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x22,
+	0x4e, 0x0a, 0x08, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x54,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x54, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x22,
+	0x8d, 0x02, 0x0a, 0x0e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6d, 0x61, 0x63, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x08, 0x64, 0x69, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x1a,
+	0x0a, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x2e, 0x0a, 0x0a, 0x6f, 0x70,
+	0x65, 0x6e, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x52,
+	0x09, 0x6f, 0x70, 0x65, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x6f,
+	0x72, 0x74, 0x73, 0x5f, 0x73, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x53, 0x63, 0x61, 0x6e, 0x6e, 0x65, 0x64, 0x12,
+	0x1e, 0x0a, 0x02, 0x6f, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x53, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x02, 0x6f, 0x73, 0x22,
+	0x47, 0x0a, 0x10, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x09, 0x61,
+	0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x22, 0x2b, 0x0a, 0x05, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c, 0x65, 0x64, 0x67, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x61, 0x63, 0x6b, 0x6e, 0x6f, 0x77, 0x6c,
+	0x65, 0x64, 0x67, 0x65, 0x64, 0x22, 0x37, 0x0a, 0x0e, 0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x6f, 0x70, 0x6c,
+	0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x52, 0x06, 0x70, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x22, 0x56,
+	0x0a, 0x06, 0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x69, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x61, 0x77, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x61, 0x77,
+	0x61, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x68, 0x6f, 0x6d, 0x65, 0x22, 0x60, 0x0a, 0x14, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50,
+	0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x68, 0x6f, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f,
+	0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x6c, 0x61, 0x73,
+	0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xad, 0x02, 0x0a, 0x0d, 0x50, 0x72, 0x65,
+	0x73, 0x65, 0x6e, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x26, 0x0a, 0x06, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x23, 0x0a, 0x03, 0x62, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x52, 0x03, 0x62, 0x6c, 0x65, 0x12, 0x25, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x73, 0x6f,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x52, 0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x65,
+	0x6d, 0x70, 0x74, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
+	0x48, 0x6f, 0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x72, 0x65, 0x76,
+	0x69, 0x6f, 0x75, 0x73, 0x48, 0x6f, 0x6d, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3d, 0x0a, 0x13, 0x41, 0x73, 0x73, 0x69,
+	0x67, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x8e, 0x05, 0x0a, 0x07, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49,
+	0x64, 0x52, 0x02, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x6f, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x48, 0x6f, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x4c, 0x61, 0x73,
+	0x74, 0x53, 0x65, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x4c, 0x61, 0x73,
+	0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x41, 0x77, 0x61, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x04, 0x41, 0x77, 0x61, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a,
+	0x06, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x53, 0x6d, 0x61, 0x72, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05,
+	0x53, 0x6d, 0x61, 0x72, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x4d, 0x61, 0x6e, 0x75, 0x66, 0x61, 0x63,
+	0x74, 0x75, 0x72, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x4d, 0x61, 0x6e,
+	0x75, 0x66, 0x61, 0x63, 0x74, 0x75, 0x72, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x50, 0x72, 0x65,
+	0x73, 0x65, 0x6e, 0x63, 0x65, 0x41, 0x77, 0x61, 0x72, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0d, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x41, 0x77, 0x61, 0x72, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x02,
+	0x52, 0x07, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x48, 0x6f, 0x73,
+	0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x48, 0x6f,
+	0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x2b, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x20, 0x0a, 0x0b, 0x41, 0x77, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x41, 0x77, 0x61, 0x79, 0x54,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x52, 0x61, 0x6e, 0x64, 0x6f, 0x6d,
+	0x69, 0x7a, 0x65, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x52, 0x61, 0x6e, 0x64,
+	0x6f, 0x6d, 0x69, 0x7a, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x43, 0x61, 0x74, 0x65, 0x67, 0x6f,
+	0x72, 0x79, 0x12, 0x2d, 0x0a, 0x09, 0x4f, 0x70, 0x65, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x18,
+	0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70,
+	0x65, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x52, 0x09, 0x4f, 0x70, 0x65, 0x6e, 0x50, 0x6f, 0x72, 0x74,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x18, 0x12, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x08, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x1e, 0x0a,
+	0x02, 0x4f, 0x53, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x4f, 0x53, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x02, 0x4f, 0x53, 0x12, 0x2d, 0x0a,
+	0x09, 0x53, 0x65, 0x65, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x50, 0x6f, 0x72,
+	0x74, 0x52, 0x09, 0x53, 0x65, 0x65, 0x6e, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18, 0x15, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x22, 0x89, 0x01, 0x0a, 0x07, 0x4f, 0x53, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x6d, 0x69,
+	0x6c, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79,
+	0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x63, 0x63, 0x75,
+	0x72, 0x61, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x63, 0x63, 0x75,
+	0x72, 0x61, 0x63, 0x79, 0x22, 0x8b, 0x01, 0x0a, 0x08, 0x4f, 0x70, 0x65, 0x6e, 0x50, 0x6f, 0x72,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x62,
+	0x61, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x61, 0x6e,
+	0x6e, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65,
+	0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65,
+	0x65, 0x6e, 0x22, 0x41, 0x0a, 0x09, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x64, 0x12,
+	0x0e, 0x0a, 0x02, 0x49, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x70, 0x12,
+	0x10, 0x0a, 0x03, 0x4d, 0x61, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x4d, 0x61,
+	0x63, 0x12, 0x12, 0x0a, 0x04, 0x55, 0x55, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x55, 0x55, 0x49, 0x44, 0x2a, 0xb5, 0x01, 0x0a, 0x11, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e,
+	0x63, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x44, 0x45, 0x56, 0x49,
+	0x43, 0x45, 0x5f, 0x41, 0x52, 0x52, 0x49, 0x56, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b,
+	0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x4c, 0x45, 0x46, 0x54, 0x10, 0x02, 0x12, 0x15, 0x0a,
+	0x11, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45, 0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x44, 0x5f, 0x48, 0x4f,
+	0x4d, 0x45, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x50, 0x45, 0x52, 0x53, 0x4f, 0x4e, 0x5f, 0x41,
+	0x52, 0x52, 0x49, 0x56, 0x45, 0x44, 0x10, 0x04, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x45, 0x52, 0x53,
+	0x4f, 0x4e, 0x5f, 0x4c, 0x45, 0x46, 0x54, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13, 0x48, 0x4f, 0x55,
+	0x53, 0x45, 0x5f, 0x45, 0x4d, 0x50, 0x54, 0x59, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44,
+	0x10, 0x06, 0x12, 0x19, 0x0a, 0x15, 0x4e, 0x45, 0x57, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45,
+	0x5f, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x56, 0x45, 0x52, 0x45, 0x44, 0x10, 0x07, 0x32, 0xb7, 0x0f,
+	0x0a, 0x0c, 0x48, 0x6f, 0x6d, 0x65, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x3e,
+	0x0a, 0x03, 0x41, 0x63, 0x6b, 0x12, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x42, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x16, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x10, 0x22, 0x0b,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x41, 0x63, 0x6b, 0x3a, 0x01, 0x2a, 0x12, 0x4a,
+	0x0a, 0x07, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x1a,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x14, 0x3a, 0x01, 0x2a, 0x22, 0x0f, 0x2f, 0x72, 0x70, 0x63, 0x2f,
+	0x76, 0x31, 0x2f, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x50, 0x0a, 0x09, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x1c,
+	0x82, 0xd3, 0xe4, 0x93, 0x02, 0x16, 0x22, 0x11, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f,
+	0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x65, 0x0a, 0x11,
+	0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x73, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x54, 0x43, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x24, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x1e, 0x22, 0x19, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
+	0x3a, 0x01, 0x2a, 0x12, 0x63, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a,
-	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2e, 0x0a, 0x0c, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x0c, 0x44, 0x65, 0x6c,
+	0x12, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x51, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x23, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1d, 0x22, 0x18, 0x2f, 0x72, 0x70,
+	0x63, 0x2f, 0x76, 0x31, 0x2f, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
+	0x51, 0x75, 0x65, 0x75, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x60, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1e, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x18, 0x22, 0x13, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x4c, 0x69, 0x73, 0x74,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x4d, 0x0a, 0x0c, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x0e, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19,
+	0x22, 0x14, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x53, 0x0a, 0x0c, 0x44, 0x65, 0x6c,
 	0x65, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
-	0x3a, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64,
-	0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74,
+	0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x1f, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x19, 0x22, 0x14, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x5f,
+	0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x51,
+	0x75, 0x65, 0x75, 0x65, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f,
+	0x22, 0x1a, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x51, 0x75, 0x65, 0x75, 0x65, 0x3a, 0x01, 0x2a, 0x12,
+	0x5f, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74,
+	0x72, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x1f, 0x22, 0x1a, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x3a, 0x01, 0x2a,
+	0x12, 0x65, 0x0a, 0x15, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x28, 0x82,
+	0xd3, 0xe4, 0x93, 0x02, 0x22, 0x22, 0x1d, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x43,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x73, 0x3a, 0x01, 0x2a, 0x12, 0x63, 0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x70, 0x6c,
+	0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x27, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x21, 0x3a, 0x01, 0x2a, 0x22, 0x1c,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65,
+	0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x5f, 0x0a, 0x12,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x64,
+	0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x25, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x1f, 0x22, 0x1a,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69,
+	0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x3a, 0x01, 0x2a, 0x12, 0x5a, 0x0a,
+	0x0a, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x12, 0x16, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d,
+	0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x65, 0x6f, 0x70,
+	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x17, 0x22, 0x12, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x4c, 0x69, 0x73, 0x74,
+	0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x3a, 0x01, 0x2a, 0x12, 0x4d, 0x0a, 0x0c, 0x54, 0x6f, 0x67,
+	0x67, 0x6c, 0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x22,
+	0x14, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x54, 0x6f, 0x67, 0x67, 0x6c, 0x65, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x3a, 0x01, 0x2a, 0x12, 0x4f, 0x0a, 0x0a, 0x48, 0x6f, 0x75, 0x73,
+	0x65, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93,
+	0x02, 0x17, 0x22, 0x12, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x48, 0x6f, 0x75, 0x73,
+	0x65, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x3a, 0x01, 0x2a, 0x12, 0x4c, 0x0a, 0x0c, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x22, 0x14,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x65,
+	0x72, 0x73, 0x6f, 0x6e, 0x3a, 0x01, 0x2a, 0x12, 0x4c, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x0d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x3a, 0x01, 0x2a, 0x22,
+	0x14, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x53, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50,
+	0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74,
 	0x72, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x12, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
-	0x64, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3d, 0x0a, 0x15, 0x43, 0x6f, 0x6d, 0x70, 0x6c,
-	0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
-	0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x52,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x19, 0x3a, 0x01, 0x2a, 0x22, 0x14, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12, 0x59, 0x0a, 0x0c, 0x41, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52,
 	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x14, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
-	0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x14,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70,
-	0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69,
-	0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x14, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x64, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
-	0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
-	0x12, 0x3d, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x6f, 0x70, 0x6c, 0x65, 0x12, 0x16,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50,
-	0x65, 0x6f, 0x70, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x2e, 0x0a, 0x0c, 0x54, 0x6f, 0x67, 0x67, 0x6c, 0x65, 0x50, 0x65, 0x72, 0x73, 0x6f, 0x6e, 0x12,
-	0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x1a,
-	0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
-	0x32, 0x0a, 0x0a, 0x48, 0x6f, 0x75, 0x73, 0x65, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x14, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x42, 0x07, 0x5a, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x3a, 0x01, 0x2a, 0x22,
+	0x14, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x5b, 0x0a, 0x0c, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x44, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x12, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4d, 0x65,
+	0x72, 0x67, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x22, 0x1f, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x19, 0x3a, 0x01, 0x2a, 0x22, 0x14, 0x2f, 0x72,
+	0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x44, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x73, 0x12, 0x63, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x65, 0x73, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x14, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63,
+	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x1d, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x17, 0x12, 0x15,
+	0x2f, 0x72, 0x70, 0x63, 0x2f, 0x76, 0x31, 0x2f, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x72, 0x65,
+	0x73, 0x65, 0x6e, 0x63, 0x65, 0x30, 0x01, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68, 0x75,
+	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x65, 0x61, 0x75, 0x6a, 0x72, 0x2f, 0x6e, 0x6d, 0x61,
+	0x70, 0x5f, 0x70, 0x72, 0x6f, 0x6d, 0x65, 0x74, 0x68, 0x65, 0x75, 0x73, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x3b, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1603,84 +2763,123 @@ func file_DeviceDetector_proto_rawDescGZIP() []byte {
 	return file_DeviceDetector_proto_rawDescData
 }
 
-var file_DeviceDetector_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_DeviceDetector_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_DeviceDetector_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
 var file_DeviceDetector_proto_goTypes = []interface{}{
-	(*StringRequest)(nil),       // 0: proto.StringRequest
-	(*BleRequest)(nil),          // 1: proto.BleRequest
-	(*GoogleAssistantCall)(nil), // 2: proto.GoogleAssistantCall
-	(*FCMCall)(nil),             // 3: proto.FCMCall
-	(*MQTTAddressRequest)(nil),  // 4: proto.MQTTAddressRequest
-	(*MQTTBleRequest)(nil),      // 5: proto.MQTTBleRequest
-	(*MQTTAgent)(nil),           // 6: proto.MQTTAgent
-	(*Metadata)(nil),            // 7: proto.Metadata
-	(*TimedCommands)(nil),       // 8: proto.TimedCommands
-	(*CQsResponse)(nil),         // 9: proto.CQsResponse
-	(*TCsResponse)(nil),         // 10: proto.TCsResponse
-	(*DevicesResponse)(nil),     // 11: proto.DevicesResponse
-	(*BleDevices)(nil),          // 12: proto.BleDevices
-	(*Commands)(nil),            // 13: proto.Commands
-	(*AddressRequest)(nil),      // 14: proto.AddressRequest
-	(*AddressesRequest)(nil),    // 15: proto.AddressesRequest
-	(*Reply)(nil),               // 16: proto.Reply
-	(*PeopleResponse)(nil),      // 17: proto.PeopleResponse
-	(*People)(nil),              // 18: proto.People
-	(*Devices)(nil),             // 19: proto.Devices
-	(*NetworkId)(nil),           // 20: proto.networkId
-	(*emptypb.Empty)(nil),       // 21: google.protobuf.Empty
+	(PresenceEventType)(0),       // 0: proto.PresenceEventType
+	(*StringRequest)(nil),        // 1: proto.StringRequest
+	(*BleRequest)(nil),           // 2: proto.BleRequest
+	(*GoogleAssistantCall)(nil),  // 3: proto.GoogleAssistantCall
+	(*FCMCall)(nil),              // 4: proto.FCMCall
+	(*MQTTAddressRequest)(nil),   // 5: proto.MQTTAddressRequest
+	(*MQTTBleRequest)(nil),       // 6: proto.MQTTBleRequest
+	(*MQTTAgent)(nil),            // 7: proto.MQTTAgent
+	(*Metadata)(nil),             // 8: proto.Metadata
+	(*TimedCommands)(nil),        // 9: proto.TimedCommands
+	(*CQsResponse)(nil),          // 10: proto.CQsResponse
+	(*TCsResponse)(nil),          // 11: proto.TCsResponse
+	(*ListDevicesRequest)(nil),   // 12: proto.ListDevicesRequest
+	(*DevicesResponse)(nil),      // 13: proto.DevicesResponse
+	(*Home)(nil),                 // 14: proto.Home
+	(*MergeDevicesRequest)(nil),  // 15: proto.MergeDevicesRequest
+	(*Bundle)(nil),               // 16: proto.Bundle
+	(*HomesResponse)(nil),        // 17: proto.HomesResponse
+	(*BleDevices)(nil),           // 18: proto.BleDevices
+	(*Commands)(nil),             // 19: proto.Commands
+	(*AddressRequest)(nil),       // 20: proto.AddressRequest
+	(*AddressesRequest)(nil),     // 21: proto.AddressesRequest
+	(*Reply)(nil),                // 22: proto.Reply
+	(*PeopleResponse)(nil),       // 23: proto.PeopleResponse
+	(*People)(nil),               // 24: proto.People
+	(*WatchPresenceRequest)(nil), // 25: proto.WatchPresenceRequest
+	(*PresenceEvent)(nil),        // 26: proto.PresenceEvent
+	(*AssignDeviceRequest)(nil),  // 27: proto.AssignDeviceRequest
+	(*Devices)(nil),              // 28: proto.Devices
+	(*OSMatch)(nil),              // 29: proto.OSMatch
+	(*OpenPort)(nil),             // 30: proto.OpenPort
+	(*NetworkId)(nil),            // 31: proto.networkId
+	(*emptypb.Empty)(nil),        // 32: google.protobuf.Empty
 }
 var file_DeviceDetector_proto_depIdxs = []int32{
-	6,  // 0: proto.MQTTAddressRequest.agent:type_name -> proto.MQTTAgent
-	14, // 1: proto.MQTTAddressRequest.addresses:type_name -> proto.AddressRequest
-	7,  // 2: proto.MQTTAddressRequest.metadata:type_name -> proto.Metadata
-	6,  // 3: proto.MQTTBleRequest.agent:type_name -> proto.MQTTAgent
-	1,  // 4: proto.MQTTBleRequest.bles:type_name -> proto.BleRequest
-	7,  // 5: proto.MQTTBleRequest.metadata:type_name -> proto.Metadata
-	8,  // 6: proto.CQsResponse.cqs:type_name -> proto.TimedCommands
-	12, // 7: proto.TCsResponse.bles:type_name -> proto.BleDevices
-	19, // 8: proto.DevicesResponse.devices:type_name -> proto.Devices
-	13, // 9: proto.BleDevices.commands:type_name -> proto.Commands
-	7,  // 10: proto.BleDevices.metadata:type_name -> proto.Metadata
-	14, // 11: proto.AddressesRequest.addresses:type_name -> proto.AddressRequest
-	18, // 12: proto.PeopleResponse.people:type_name -> proto.People
-	20, // 13: proto.Devices.Id:type_name -> proto.networkId
-	7,  // 14: proto.Devices.metadata:type_name -> proto.Metadata
-	1,  // 15: proto.HomeDetector.Ack:input_type -> proto.BleRequest
-	14, // 16: proto.HomeDetector.Address:input_type -> proto.AddressRequest
-	15, // 17: proto.HomeDetector.Addresses:input_type -> proto.AddressesRequest
-	21, // 18: proto.HomeDetector.ListTimedCommands:input_type -> google.protobuf.Empty
-	21, // 19: proto.HomeDetector.ListCommandQueue:input_type -> google.protobuf.Empty
-	21, // 20: proto.HomeDetector.ListDevices:input_type -> google.protobuf.Empty
-	19, // 21: proto.HomeDetector.UpdateDevice:input_type -> proto.Devices
-	0,  // 22: proto.HomeDetector.DeleteDevice:input_type -> proto.StringRequest
-	0,  // 23: proto.HomeDetector.DeleteCommandQueue:input_type -> proto.StringRequest
-	0,  // 24: proto.HomeDetector.DeleteTimedCommand:input_type -> proto.StringRequest
-	0,  // 25: proto.HomeDetector.CompleteTimedCommands:input_type -> proto.StringRequest
-	0,  // 26: proto.HomeDetector.CompleteTimedCommand:input_type -> proto.StringRequest
-	8,  // 27: proto.HomeDetector.CreateTimedCommand:input_type -> proto.TimedCommands
-	21, // 28: proto.HomeDetector.ListPeople:input_type -> google.protobuf.Empty
-	19, // 29: proto.HomeDetector.TogglePerson:input_type -> proto.Devices
-	0,  // 30: proto.HomeDetector.HouseEmpty:input_type -> proto.StringRequest
-	16, // 31: proto.HomeDetector.Ack:output_type -> proto.Reply
-	16, // 32: proto.HomeDetector.Address:output_type -> proto.Reply
-	16, // 33: proto.HomeDetector.Addresses:output_type -> proto.Reply
-	10, // 34: proto.HomeDetector.ListTimedCommands:output_type -> proto.TCsResponse
-	9,  // 35: proto.HomeDetector.ListCommandQueue:output_type -> proto.CQsResponse
-	11, // 36: proto.HomeDetector.ListDevices:output_type -> proto.DevicesResponse
-	16, // 37: proto.HomeDetector.UpdateDevice:output_type -> proto.Reply
-	16, // 38: proto.HomeDetector.DeleteDevice:output_type -> proto.Reply
-	16, // 39: proto.HomeDetector.DeleteCommandQueue:output_type -> proto.Reply
-	16, // 40: proto.HomeDetector.DeleteTimedCommand:output_type -> proto.Reply
-	16, // 41: proto.HomeDetector.CompleteTimedCommands:output_type -> proto.Reply
-	16, // 42: proto.HomeDetector.CompleteTimedCommand:output_type -> proto.Reply
-	16, // 43: proto.HomeDetector.CreateTimedCommand:output_type -> proto.Reply
-	17, // 44: proto.HomeDetector.ListPeople:output_type -> proto.PeopleResponse
-	16, // 45: proto.HomeDetector.TogglePerson:output_type -> proto.Reply
-	16, // 46: proto.HomeDetector.HouseEmpty:output_type -> proto.Reply
-	31, // [31:47] is the sub-list for method output_type
-	15, // [15:31] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	7,  // 0: proto.MQTTAddressRequest.agent:type_name -> proto.MQTTAgent
+	20, // 1: proto.MQTTAddressRequest.addresses:type_name -> proto.AddressRequest
+	8,  // 2: proto.MQTTAddressRequest.metadata:type_name -> proto.Metadata
+	7,  // 3: proto.MQTTBleRequest.agent:type_name -> proto.MQTTAgent
+	2,  // 4: proto.MQTTBleRequest.bles:type_name -> proto.BleRequest
+	8,  // 5: proto.MQTTBleRequest.metadata:type_name -> proto.Metadata
+	9,  // 6: proto.CQsResponse.cqs:type_name -> proto.TimedCommands
+	18, // 7: proto.TCsResponse.bles:type_name -> proto.BleDevices
+	28, // 8: proto.DevicesResponse.devices:type_name -> proto.Devices
+	28, // 9: proto.Bundle.devices:type_name -> proto.Devices
+	18, // 10: proto.Bundle.bles:type_name -> proto.BleDevices
+	24, // 11: proto.Bundle.people:type_name -> proto.People
+	14, // 12: proto.Bundle.homes:type_name -> proto.Home
+	9,  // 13: proto.Bundle.commands:type_name -> proto.TimedCommands
+	14, // 14: proto.HomesResponse.homes:type_name -> proto.Home
+	19, // 15: proto.BleDevices.commands:type_name -> proto.Commands
+	8,  // 16: proto.BleDevices.metadata:type_name -> proto.Metadata
+	30, // 17: proto.AddressRequest.open_ports:type_name -> proto.OpenPort
+	29, // 18: proto.AddressRequest.os:type_name -> proto.OSMatch
+	20, // 19: proto.AddressesRequest.addresses:type_name -> proto.AddressRequest
+	24, // 20: proto.PeopleResponse.people:type_name -> proto.People
+	0,  // 21: proto.PresenceEvent.type:type_name -> proto.PresenceEventType
+	28, // 22: proto.PresenceEvent.device:type_name -> proto.Devices
+	18, // 23: proto.PresenceEvent.ble:type_name -> proto.BleDevices
+	24, // 24: proto.PresenceEvent.person:type_name -> proto.People
+	31, // 25: proto.Devices.Id:type_name -> proto.networkId
+	8,  // 26: proto.Devices.metadata:type_name -> proto.Metadata
+	30, // 27: proto.Devices.OpenPorts:type_name -> proto.OpenPort
+	29, // 28: proto.Devices.OS:type_name -> proto.OSMatch
+	30, // 29: proto.Devices.SeenPorts:type_name -> proto.OpenPort
+	2,  // 30: proto.HomeDetector.Ack:input_type -> proto.BleRequest
+	20, // 31: proto.HomeDetector.Address:input_type -> proto.AddressRequest
+	21, // 32: proto.HomeDetector.Addresses:input_type -> proto.AddressesRequest
+	32, // 33: proto.HomeDetector.ListTimedCommands:input_type -> google.protobuf.Empty
+	32, // 34: proto.HomeDetector.ListCommandQueue:input_type -> google.protobuf.Empty
+	12, // 35: proto.HomeDetector.ListDevices:input_type -> proto.ListDevicesRequest
+	28, // 36: proto.HomeDetector.UpdateDevice:input_type -> proto.Devices
+	1,  // 37: proto.HomeDetector.DeleteDevice:input_type -> proto.StringRequest
+	1,  // 38: proto.HomeDetector.DeleteCommandQueue:input_type -> proto.StringRequest
+	1,  // 39: proto.HomeDetector.DeleteTimedCommand:input_type -> proto.StringRequest
+	1,  // 40: proto.HomeDetector.CompleteTimedCommands:input_type -> proto.StringRequest
+	1,  // 41: proto.HomeDetector.CompleteTimedCommand:input_type -> proto.StringRequest
+	9,  // 42: proto.HomeDetector.CreateTimedCommand:input_type -> proto.TimedCommands
+	32, // 43: proto.HomeDetector.ListPeople:input_type -> google.protobuf.Empty
+	28, // 44: proto.HomeDetector.TogglePerson:input_type -> proto.Devices
+	1,  // 45: proto.HomeDetector.HouseEmpty:input_type -> proto.StringRequest
+	24, // 46: proto.HomeDetector.CreatePerson:input_type -> proto.People
+	24, // 47: proto.HomeDetector.UpdatePerson:input_type -> proto.People
+	1,  // 48: proto.HomeDetector.DeletePerson:input_type -> proto.StringRequest
+	27, // 49: proto.HomeDetector.AssignDevice:input_type -> proto.AssignDeviceRequest
+	15, // 50: proto.HomeDetector.MergeDevices:input_type -> proto.MergeDevicesRequest
+	25, // 51: proto.HomeDetector.WatchPresence:input_type -> proto.WatchPresenceRequest
+	22, // 52: proto.HomeDetector.Ack:output_type -> proto.Reply
+	22, // 53: proto.HomeDetector.Address:output_type -> proto.Reply
+	22, // 54: proto.HomeDetector.Addresses:output_type -> proto.Reply
+	11, // 55: proto.HomeDetector.ListTimedCommands:output_type -> proto.TCsResponse
+	10, // 56: proto.HomeDetector.ListCommandQueue:output_type -> proto.CQsResponse
+	13, // 57: proto.HomeDetector.ListDevices:output_type -> proto.DevicesResponse
+	22, // 58: proto.HomeDetector.UpdateDevice:output_type -> proto.Reply
+	22, // 59: proto.HomeDetector.DeleteDevice:output_type -> proto.Reply
+	22, // 60: proto.HomeDetector.DeleteCommandQueue:output_type -> proto.Reply
+	22, // 61: proto.HomeDetector.DeleteTimedCommand:output_type -> proto.Reply
+	22, // 62: proto.HomeDetector.CompleteTimedCommands:output_type -> proto.Reply
+	22, // 63: proto.HomeDetector.CompleteTimedCommand:output_type -> proto.Reply
+	22, // 64: proto.HomeDetector.CreateTimedCommand:output_type -> proto.Reply
+	23, // 65: proto.HomeDetector.ListPeople:output_type -> proto.PeopleResponse
+	22, // 66: proto.HomeDetector.TogglePerson:output_type -> proto.Reply
+	22, // 67: proto.HomeDetector.HouseEmpty:output_type -> proto.Reply
+	22, // 68: proto.HomeDetector.CreatePerson:output_type -> proto.Reply
+	22, // 69: proto.HomeDetector.UpdatePerson:output_type -> proto.Reply
+	22, // 70: proto.HomeDetector.DeletePerson:output_type -> proto.Reply
+	22, // 71: proto.HomeDetector.AssignDevice:output_type -> proto.Reply
+	28, // 72: proto.HomeDetector.MergeDevices:output_type -> proto.Devices
+	26, // 73: proto.HomeDetector.WatchPresence:output_type -> proto.PresenceEvent
+	52, // [52:74] is the sub-list for method output_type
+	30, // [30:52] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_DeviceDetector_proto_init() }
@@ -1822,7 +3021,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DevicesResponse); i {
+			switch v := v.(*ListDevicesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1834,7 +3033,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BleDevices); i {
+			switch v := v.(*DevicesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1846,7 +3045,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Commands); i {
+			switch v := v.(*Home); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1858,7 +3057,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddressRequest); i {
+			switch v := v.(*MergeDevicesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1870,7 +3069,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AddressesRequest); i {
+			switch v := v.(*Bundle); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1882,7 +3081,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Reply); i {
+			switch v := v.(*HomesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1894,7 +3093,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PeopleResponse); i {
+			switch v := v.(*BleDevices); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1906,7 +3105,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*People); i {
+			switch v := v.(*Commands); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1918,7 +3117,7 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Devices); i {
+			switch v := v.(*AddressRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1930,6 +3129,126 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 		file_DeviceDetector_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddressesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Reply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeopleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*People); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchPresenceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresenceEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssignDeviceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Devices); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OSMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OpenPort); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_DeviceDetector_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*NetworkId); i {
 			case 0:
 				return &v.state
@@ -1942,18 +3261,20 @@ func file_DeviceDetector_proto_init() {
 			}
 		}
 	}
+	file_DeviceDetector_proto_msgTypes[11].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_DeviceDetector_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   21,
+			NumEnums:      1,
+			NumMessages:   31,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_DeviceDetector_proto_goTypes,
 		DependencyIndexes: file_DeviceDetector_proto_depIdxs,
+		EnumInfos:         file_DeviceDetector_proto_enumTypes,
 		MessageInfos:      file_DeviceDetector_proto_msgTypes,
 	}.Build()
 	File_DeviceDetector_proto = out.File
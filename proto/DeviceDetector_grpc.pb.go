@@ -24,7 +24,7 @@ type HomeDetectorClient interface {
 	Addresses(ctx context.Context, in *AddressesRequest, opts ...grpc.CallOption) (*Reply, error)
 	ListTimedCommands(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TCsResponse, error)
 	ListCommandQueue(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CQsResponse, error)
-	ListDevices(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DevicesResponse, error)
+	ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*DevicesResponse, error)
 	UpdateDevice(ctx context.Context, in *Devices, opts ...grpc.CallOption) (*Reply, error)
 	DeleteDevice(ctx context.Context, in *StringRequest, opts ...grpc.CallOption) (*Reply, error)
 	DeleteCommandQueue(ctx context.Context, in *StringRequest, opts ...grpc.CallOption) (*Reply, error)
@@ -35,6 +35,12 @@ type HomeDetectorClient interface {
 	ListPeople(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PeopleResponse, error)
 	TogglePerson(ctx context.Context, in *Devices, opts ...grpc.CallOption) (*Reply, error)
 	HouseEmpty(ctx context.Context, in *StringRequest, opts ...grpc.CallOption) (*Reply, error)
+	CreatePerson(ctx context.Context, in *People, opts ...grpc.CallOption) (*Reply, error)
+	UpdatePerson(ctx context.Context, in *People, opts ...grpc.CallOption) (*Reply, error)
+	DeletePerson(ctx context.Context, in *StringRequest, opts ...grpc.CallOption) (*Reply, error)
+	AssignDevice(ctx context.Context, in *AssignDeviceRequest, opts ...grpc.CallOption) (*Reply, error)
+	MergeDevices(ctx context.Context, in *MergeDevicesRequest, opts ...grpc.CallOption) (*Devices, error)
+	WatchPresence(ctx context.Context, in *WatchPresenceRequest, opts ...grpc.CallOption) (HomeDetector_WatchPresenceClient, error)
 }
 
 type homeDetectorClient struct {
@@ -90,7 +96,7 @@ func (c *homeDetectorClient) ListCommandQueue(ctx context.Context, in *emptypb.E
 	return out, nil
 }
 
-func (c *homeDetectorClient) ListDevices(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DevicesResponse, error) {
+func (c *homeDetectorClient) ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*DevicesResponse, error) {
 	out := new(DevicesResponse)
 	err := c.cc.Invoke(ctx, "/proto.HomeDetector/ListDevices", in, out, opts...)
 	if err != nil {
@@ -189,6 +195,83 @@ func (c *homeDetectorClient) HouseEmpty(ctx context.Context, in *StringRequest,
 	return out, nil
 }
 
+func (c *homeDetectorClient) CreatePerson(ctx context.Context, in *People, opts ...grpc.CallOption) (*Reply, error) {
+	out := new(Reply)
+	err := c.cc.Invoke(ctx, "/proto.HomeDetector/CreatePerson", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *homeDetectorClient) UpdatePerson(ctx context.Context, in *People, opts ...grpc.CallOption) (*Reply, error) {
+	out := new(Reply)
+	err := c.cc.Invoke(ctx, "/proto.HomeDetector/UpdatePerson", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *homeDetectorClient) DeletePerson(ctx context.Context, in *StringRequest, opts ...grpc.CallOption) (*Reply, error) {
+	out := new(Reply)
+	err := c.cc.Invoke(ctx, "/proto.HomeDetector/DeletePerson", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *homeDetectorClient) AssignDevice(ctx context.Context, in *AssignDeviceRequest, opts ...grpc.CallOption) (*Reply, error) {
+	out := new(Reply)
+	err := c.cc.Invoke(ctx, "/proto.HomeDetector/AssignDevice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *homeDetectorClient) MergeDevices(ctx context.Context, in *MergeDevicesRequest, opts ...grpc.CallOption) (*Devices, error) {
+	out := new(Devices)
+	err := c.cc.Invoke(ctx, "/proto.HomeDetector/MergeDevices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *homeDetectorClient) WatchPresence(ctx context.Context, in *WatchPresenceRequest, opts ...grpc.CallOption) (HomeDetector_WatchPresenceClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_HomeDetector_serviceDesc.Streams[0], "/proto.HomeDetector/WatchPresence", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &homeDetectorWatchPresenceClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type HomeDetector_WatchPresenceClient interface {
+	Recv() (*PresenceEvent, error)
+	grpc.ClientStream
+}
+
+type homeDetectorWatchPresenceClient struct {
+	grpc.ClientStream
+}
+
+func (x *homeDetectorWatchPresenceClient) Recv() (*PresenceEvent, error) {
+	m := new(PresenceEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // HomeDetectorServer is the server API for HomeDetector service.
 // All implementations must embed UnimplementedHomeDetectorServer
 // for forward compatibility
@@ -199,7 +282,7 @@ type HomeDetectorServer interface {
 	Addresses(context.Context, *AddressesRequest) (*Reply, error)
 	ListTimedCommands(context.Context, *emptypb.Empty) (*TCsResponse, error)
 	ListCommandQueue(context.Context, *emptypb.Empty) (*CQsResponse, error)
-	ListDevices(context.Context, *emptypb.Empty) (*DevicesResponse, error)
+	ListDevices(context.Context, *ListDevicesRequest) (*DevicesResponse, error)
 	UpdateDevice(context.Context, *Devices) (*Reply, error)
 	DeleteDevice(context.Context, *StringRequest) (*Reply, error)
 	DeleteCommandQueue(context.Context, *StringRequest) (*Reply, error)
@@ -210,6 +293,12 @@ type HomeDetectorServer interface {
 	ListPeople(context.Context, *emptypb.Empty) (*PeopleResponse, error)
 	TogglePerson(context.Context, *Devices) (*Reply, error)
 	HouseEmpty(context.Context, *StringRequest) (*Reply, error)
+	CreatePerson(context.Context, *People) (*Reply, error)
+	UpdatePerson(context.Context, *People) (*Reply, error)
+	DeletePerson(context.Context, *StringRequest) (*Reply, error)
+	AssignDevice(context.Context, *AssignDeviceRequest) (*Reply, error)
+	MergeDevices(context.Context, *MergeDevicesRequest) (*Devices, error)
+	WatchPresence(*WatchPresenceRequest, HomeDetector_WatchPresenceServer) error
 	mustEmbedUnimplementedHomeDetectorServer()
 }
 
@@ -232,7 +321,7 @@ func (UnimplementedHomeDetectorServer) ListTimedCommands(context.Context, *empty
 func (UnimplementedHomeDetectorServer) ListCommandQueue(context.Context, *emptypb.Empty) (*CQsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListCommandQueue not implemented")
 }
-func (UnimplementedHomeDetectorServer) ListDevices(context.Context, *emptypb.Empty) (*DevicesResponse, error) {
+func (UnimplementedHomeDetectorServer) ListDevices(context.Context, *ListDevicesRequest) (*DevicesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListDevices not implemented")
 }
 func (UnimplementedHomeDetectorServer) UpdateDevice(context.Context, *Devices) (*Reply, error) {
@@ -265,6 +354,24 @@ func (UnimplementedHomeDetectorServer) TogglePerson(context.Context, *Devices) (
 func (UnimplementedHomeDetectorServer) HouseEmpty(context.Context, *StringRequest) (*Reply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HouseEmpty not implemented")
 }
+func (UnimplementedHomeDetectorServer) CreatePerson(context.Context, *People) (*Reply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePerson not implemented")
+}
+func (UnimplementedHomeDetectorServer) UpdatePerson(context.Context, *People) (*Reply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePerson not implemented")
+}
+func (UnimplementedHomeDetectorServer) DeletePerson(context.Context, *StringRequest) (*Reply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePerson not implemented")
+}
+func (UnimplementedHomeDetectorServer) AssignDevice(context.Context, *AssignDeviceRequest) (*Reply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignDevice not implemented")
+}
+func (UnimplementedHomeDetectorServer) MergeDevices(context.Context, *MergeDevicesRequest) (*Devices, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeDevices not implemented")
+}
+func (UnimplementedHomeDetectorServer) WatchPresence(*WatchPresenceRequest, HomeDetector_WatchPresenceServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPresence not implemented")
+}
 func (UnimplementedHomeDetectorServer) mustEmbedUnimplementedHomeDetectorServer() {}
 
 // UnsafeHomeDetectorServer may be embedded to opt out of forward compatibility for this service.
@@ -369,7 +476,7 @@ func _HomeDetector_ListCommandQueue_Handler(srv interface{}, ctx context.Context
 }
 
 func _HomeDetector_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+	in := new(ListDevicesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -381,7 +488,7 @@ func _HomeDetector_ListDevices_Handler(srv interface{}, ctx context.Context, dec
 		FullMethod: "/proto.HomeDetector/ListDevices",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HomeDetectorServer).ListDevices(ctx, req.(*emptypb.Empty))
+		return srv.(HomeDetectorServer).ListDevices(ctx, req.(*ListDevicesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -566,6 +673,117 @@ func _HomeDetector_HouseEmpty_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HomeDetector_CreatePerson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(People)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HomeDetectorServer).CreatePerson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.HomeDetector/CreatePerson",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HomeDetectorServer).CreatePerson(ctx, req.(*People))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HomeDetector_UpdatePerson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(People)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HomeDetectorServer).UpdatePerson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.HomeDetector/UpdatePerson",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HomeDetectorServer).UpdatePerson(ctx, req.(*People))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HomeDetector_DeletePerson_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StringRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HomeDetectorServer).DeletePerson(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.HomeDetector/DeletePerson",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HomeDetectorServer).DeletePerson(ctx, req.(*StringRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HomeDetector_AssignDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HomeDetectorServer).AssignDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.HomeDetector/AssignDevice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HomeDetectorServer).AssignDevice(ctx, req.(*AssignDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HomeDetector_MergeDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HomeDetectorServer).MergeDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.HomeDetector/MergeDevices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HomeDetectorServer).MergeDevices(ctx, req.(*MergeDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HomeDetector_WatchPresence_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPresenceRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HomeDetectorServer).WatchPresence(m, &homeDetectorWatchPresenceServer{stream})
+}
+
+type HomeDetector_WatchPresenceServer interface {
+	Send(*PresenceEvent) error
+	grpc.ServerStream
+}
+
+type homeDetectorWatchPresenceServer struct {
+	grpc.ServerStream
+}
+
+func (x *homeDetectorWatchPresenceServer) Send(m *PresenceEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _HomeDetector_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "proto.HomeDetector",
 	HandlerType: (*HomeDetectorServer)(nil),
@@ -634,7 +852,33 @@ var _HomeDetector_serviceDesc = grpc.ServiceDesc{
 			MethodName: "HouseEmpty",
 			Handler:    _HomeDetector_HouseEmpty_Handler,
 		},
+		{
+			MethodName: "CreatePerson",
+			Handler:    _HomeDetector_CreatePerson_Handler,
+		},
+		{
+			MethodName: "UpdatePerson",
+			Handler:    _HomeDetector_UpdatePerson_Handler,
+		},
+		{
+			MethodName: "DeletePerson",
+			Handler:    _HomeDetector_DeletePerson_Handler,
+		},
+		{
+			MethodName: "AssignDevice",
+			Handler:    _HomeDetector_AssignDevice_Handler,
+		},
+		{
+			MethodName: "MergeDevices",
+			Handler:    _HomeDetector_MergeDevices_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPresence",
+			Handler:       _HomeDetector_WatchPresence_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "DeviceDetector.proto",
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/beaujr/nmap_prometheus/house"
+	"io"
+	"os"
+)
+
+const adminUsage = `usage: admin export [-format yaml|json] [-o file]
+       admin import [-replace] [-dry-run] <file|->
+       admin update-oui <oui.csv> [mam.csv] [oui36.csv]`
+
+// admin runs the admin export and import subcommands against the -store and
+// update-oui against the -ouiDatabase
+func admin(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(adminUsage)
+	}
+	ctx := context.Background()
+	switch args[0] {
+	case "export":
+		flags := flag.NewFlagSet("export", flag.ExitOnError)
+		format := flags.String("format", "yaml", "Bundle format: yaml or json")
+		output := flags.String("o", "", "File to write the bundle to, blank for stdout")
+		flags.Parse(args[1:])
+		store, err := house.NewStore()
+		if err != nil {
+			return err
+		}
+		bundle, err := house.ExportBundle(ctx, store)
+		if err != nil {
+			return err
+		}
+		data, err := house.MarshalBundle(bundle, *format)
+		if err != nil {
+			return err
+		}
+		if *output == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		return os.WriteFile(*output, data, 0600)
+	case "import":
+		flags := flag.NewFlagSet("import", flag.ExitOnError)
+		replace := flags.Bool("replace", false, "Delete the records missing from the bundle rather than merging")
+		dryRun := flags.Bool("dry-run", false, "Print the changes without writing them")
+		flags.Parse(args[1:])
+		if flags.NArg() != 1 {
+			return fmt.Errorf(adminUsage)
+		}
+		var data []byte
+		var err error
+		if flags.Arg(0) == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(flags.Arg(0))
+		}
+		if err != nil {
+			return err
+		}
+		bundle, err := house.UnmarshalBundle(data)
+		if err != nil {
+			return err
+		}
+		store, err := house.NewStore()
+		if err != nil {
+			return err
+		}
+		changes, err := house.ImportBundle(ctx, store, bundle, *replace, *dryRun)
+		for _, change := range changes {
+			fmt.Println(change)
+		}
+		return err
+	case "update-oui":
+		if len(args) < 2 {
+			return fmt.Errorf(adminUsage)
+		}
+		count, err := house.UpdateOUIDatabase(args[1:]...)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("wrote %d assignments\n", count)
+		return nil
+	}
+	return fmt.Errorf(adminUsage)
+}
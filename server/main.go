@@ -4,11 +4,14 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"github.com/beaujr/nmap_prometheus/broker"
+	"github.com/beaujr/nmap_prometheus/certs"
 	"github.com/beaujr/nmap_prometheus/house"
 	pb "github.com/beaujr/nmap_prometheus/proto"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 	"log"
 	"net"
@@ -19,27 +22,89 @@ import (
 
 var port = flag.String("port", "50051", "Port for GRPC Server")
 var apiPort = flag.String("apiPort", "2112", "Port for API Server")
+var migrate = flag.Bool("migrate", false, "Rewrite the stored records in the -recordFormat and exit")
 
 func main() {
 	flag.Parse()
+	if flag.Arg(0) == "admin" {
+		if err := admin(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if *migrate {
+		store, err := house.NewStore()
+		if err != nil {
+			log.Fatalf("failed to open the store: %v", err)
+		}
+		count, err := house.MigrateRecords(context.Background(), store)
+		if err != nil {
+			log.Fatalf("failed to migrate records after %d: %v", count, err)
+		}
+		log.Printf("migrated %d records", count)
+		return
+	}
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", *port))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer(
-		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
-	)
+	keys, err := house.ReadKeyStore()
+	if err != nil {
+		log.Fatalf("failed to read api keys: %v", err)
+	}
+	reloader, err := certs.FromFlags()
+	if err != nil {
+		log.Fatalf("failed to load certificates: %v", err)
+	}
+	opts := []grpc.ServerOption{}
+	unary := []grpc.UnaryServerInterceptor{grpc_prometheus.UnaryServerInterceptor}
+	stream := []grpc.StreamServerInterceptor{grpc_prometheus.StreamServerInterceptor}
+	if reloader != nil {
+		if !reloader.HasCertificate() {
+			log.Fatal("-tls-cert and -tls-key are required to serve TLS")
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+		unary = append(unary, house.UnaryIdentityInterceptor)
+		stream = append(stream, house.StreamIdentityInterceptor)
+	}
+	if keys != nil {
+		unary = append(unary, house.UnaryAuthInterceptor(keys))
+		stream = append(stream, house.StreamAuthInterceptor(keys))
+	} else {
+		log.Println("no -apikeys file, accepting unauthenticated requests")
+	}
+	opts = append(opts, grpc.ChainUnaryInterceptor(unary...), grpc.ChainStreamInterceptor(stream...))
+	s := grpc.NewServer(opts...)
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 	server := house.NewServer(ctx)
 	// Register reflection service on gRPC server.
 	reflection.Register(s)
 	pb.RegisterHomeDetectorServer(s, server.(pb.HomeDetectorServer))
+	if broker.Enabled() {
+		client, err := broker.Connect("nmap_prometheus-server", server.(*house.Server).SubscribeMQTT)
+		if err != nil {
+			log.Fatalf("failed to connect to mqtt: %v", err)
+		}
+		server.(*house.Server).EnableHomeAssistant(client)
+	}
 	http.Handle("/metrics", promhttp.Handler())
-	http.HandleFunc("/devices", server.Devices)
+	http.Handle("/devices", house.RequireAPIKey(keys, http.HandlerFunc(server.Devices)))
 	http.HandleFunc("/people", server.People)
 	http.HandleFunc("/empty", server.HomeEmptyState)
+	http.Handle("/events", house.RequireStreamKey(keys, http.HandlerFunc(server.Events)))
+	http.Handle("/api/v1/", house.RequireAPIKey(keys, http.HandlerFunc(server.API)))
+	if keys != nil {
+		gateway, err := house.Gateway(ctx, server.(pb.HomeDetectorServer), keys, unary, stream)
+		if err != nil {
+			log.Fatalf("failed to start the gateway: %v", err)
+		}
+		http.Handle("/rpc/", gateway)
+	} else {
+		log.Println("no -apikeys file, not serving the gateway on /rpc/")
+	}
+	http.Handle("/dashboard/", http.StripPrefix("/dashboard/", house.Dashboard()))
 	go http.ListenAndServe(fmt.Sprintf(":%s", *apiPort), nil)
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)